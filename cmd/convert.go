@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"lem-in/functions"
+)
+
+// convertCommand implements "lem-in convert -to text|json [-o file] <map>": it reads a colony in either format,
+// the format being detected from its content, and writes it in the requested one to the output file or to the standard output.
+// Converting to JSON warns on the standard error about the tunnel comments and trailing comments it drops.
+func convertCommand(args []string) {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	to := flags.String("to", "", "output format: text (lem-in) or json")
+	output := flags.String("o", "", "output file (default: standard output)")
+	strictness := flags.String("strictness", "strict", "input strictness for text colonies: strict or relaxed")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Println("ERROR: expected one argument (file name)")
+		os.Exit(2)
+	}
+	if *to != "text" && *to != "json" {
+		fmt.Println("ERROR: expected an output format, -to text or -to json")
+		os.Exit(2)
+	}
+	strictInput, err := parseStrictness(*strictness)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	Colony, err := functions.ParseFileOptions(flags.Arg(0), functions.ParseOptions{Strictness: strictInput})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if *to == "json" {
+		dropped := len(Colony.Graph.Comments)
+		for _, edge := range Colony.Graph.Edges {
+			dropped += len(edge.Comments)
+		}
+		if dropped > 0 {
+			fmt.Fprintf(os.Stderr, "WARNING: %d comment lines in front of tunnels or at the end of %s have no place in JSON and are dropped\n", dropped, flags.Arg(0))
+		}
+	}
+	err = writeOutput(*output, func(w io.Writer) error {
+		if *to == "json" {
+			return functions.WriteColonyJSON(w, Colony)
+		}
+		return functions.WriteColony(w, Colony)
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
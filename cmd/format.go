@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"lem-in/functions"
+)
+
+// fmtCommand implements "lem-in fmt [-s] [-l] [-d] [-w] [map...]": like gofmt, it prints each colony file in canonical form,
+// lists the files whose formatting differs (-l), prints the changes as a unified diff (-d) or rewrites the files in place (-w).
+// Without file arguments it formats the standard input. It exits with status 1 when a file can't be read or parsed.
+func fmtCommand(args []string) {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	sorted := flags.Bool("s", false, "sort the rooms by name and the tunnels by their rooms instead of keeping the declaration order")
+	list := flags.Bool("l", false, "list the files whose formatting differs from the canonical form")
+	diff := flags.Bool("d", false, "print the changes as a unified diff instead of the formatted files")
+	write := flags.Bool("w", false, "write the result back to the files instead of printing it")
+	flags.Parse(args)
+	paths := flags.Args()
+	if len(paths) == 0 {
+		if *write {
+			fmt.Println("ERROR: -w needs file arguments")
+			os.Exit(2)
+		}
+		paths = []string{"-"}
+	}
+	failed := false
+	for _, path := range paths {
+		if err := formatFile(path, functions.WriteOptions{Sorted: *sorted}, *list, *diff, *write); err != nil {
+			fmt.Printf("%s: %v\n", path, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// formatFile formats the colony file at path ("-" for the standard input) and reports the result as selected by the flags,
+// a gzip-compressed file stays compressed when it is rewritten.
+func formatFile(path string, opts functions.WriteOptions, list, diff, write bool) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("ERROR: failed to read file: %v", err)
+	}
+	reader, closer, err := functions.Decompress(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("ERROR: failed to decompress file: %v", err)
+	}
+	original, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("ERROR: failed to decompress file: %v", err)
+	}
+	formatted, err := functions.FormatColony(bytes.NewReader(original), opts)
+	if err != nil {
+		return err
+	}
+	changed := !bytes.Equal(original, formatted)
+	if list && changed {
+		fmt.Println(path)
+	}
+	if diff && changed {
+		if err := printDiff(path, original, formatted); err != nil {
+			return err
+		}
+	}
+	if write && changed {
+		if closer != nil {
+			var compressed bytes.Buffer
+			gz := gzip.NewWriter(&compressed)
+			gz.Write(formatted)
+			gz.Close()
+			formatted = compressed.Bytes()
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("ERROR: failed to write file: %v", err)
+		}
+		if err := os.WriteFile(path, formatted, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("ERROR: failed to write file: %v", err)
+		}
+	}
+	if !list && !diff && !write {
+		os.Stdout.Write(formatted)
+	}
+	return nil
+}
+
+// printDiff prints a unified diff between the original and the formatted content of the file at path with the diff command,
+// as gofmt -d did, the two versions are written to temporary files first.
+func printDiff(path string, original, formatted []byte) error {
+	before, err := writeTemp(original)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(before)
+	after, err := writeTemp(formatted)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(after)
+	out, err := exec.Command("diff", "-u", "--label", path+".orig", "--label", path, before, after).Output()
+	if len(out) > 0 {
+		// diff exits with status 1 when the files differ, which is always the case here
+		os.Stdout.Write(out)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("ERROR: failed to run diff: %v", err)
+	}
+	return nil
+}
+
+// writeTemp writes data to a new temporary file and returns its name.
+func writeTemp(data []byte) (string, error) {
+	file, err := os.CreateTemp("", "lem-in-fmt")
+	if err != nil {
+		return "", fmt.Errorf("ERROR: failed to create temporary file: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("ERROR: failed to write temporary file: %v", err)
+	}
+	return file.Name(), nil
+}
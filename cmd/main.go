@@ -1,42 +1,262 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"strings"
 
+	"lem-in/entities"
 	"lem-in/functions"
+	"lem-in/verifier"
 )
 
-// This function parses a graph, start and end points, number of ants, and handles any errors.
-// It prints the initial data and then initializes a Colony structure, retrieves the shortest paths from the graph, sorts them, cleans duplicates, generates path combinations, deploys the ants, and finally prints the movement of the ant army.
+// This function parses the colony files given as arguments (graph, start and end points, number of ants) and handles any errors,
+// "-" reading the colony from the standard input and gzip-compressed files being decompressed on the fly.
+// It solves each colony with the solver selected by --solver, then prints the initial data followed by the movement of the ant army,
+// under a "==> file <==" header when several files are given. With several files, each JSON document and each event
+// also names the file it comes from. A map that fails doesn't stop the others, but the exit status is then 1.
 func main() {
-	graph, text, Start, End, NumberOfAnts, err := functions.Parser()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "verify":
+			verifyCommand(os.Args[2:])
+			return
+		case "render":
+			renderCommand(os.Args[2:])
+			return
+		case "serve":
+			serveCommand(os.Args[2:])
+			return
+		case "tui":
+			tuiCommand(os.Args[2:])
+			return
+		case "fmt":
+			fmtCommand(os.Args[2:])
+			return
+		case "convert":
+			convertCommand(os.Args[2:])
+			return
+		}
+	}
+	check := flag.Bool("check", false, "report every problem found in the colony file instead of solving it")
+	solverName := flag.String("solver", "default", "path selection algorithm, one of: "+strings.Join(functions.SolverNames(), ", "))
+	tunnels := flag.String("tunnels", "strict", "tunnel rule: strict (one ant per tunnel per turn) or lenient (the start-end tunnel can carry every ant at once)")
+	strictness := flag.String("strictness", "strict", "input strictness: strict (as the reference checker) or relaxed (trims whitespace, skips blank lines, warns about unknown ## commands)")
+	format := flag.String("format", "text", "output format: text (echoed colony and Lx-y moves) or json")
+	explain := flag.Bool("explain", false, "print the candidate path combinations and why the chosen one won instead of the moves")
+	events := flag.String("events", "", "also write the simulation as newline-delimited JSON events (turn_start, ant_spawn, ant_move, ant_arrive, turn_end) to this file")
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		fmt.Println("ERROR: invalid data format, expected at least one argument (file name, or - for the standard input)")
+		return
+	}
+	strictInput, err := parseStrictness(*strictness)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	Colony := *functions.NewColony(graph, Start, End, NumberOfAnts)
-	shortestPaths := [][]string{}
-	errors := []string{}
-	for _, vertex := range graph.GetVertex(Start).Adjacent {
-		path, err := graph.GetShortPath(vertex.Key, End, Start)
+	if *check {
+		failed := false
+		for _, path := range args {
+			if !checkColony(path, strictInput) {
+				failed = true
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+		return
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Printf("ERROR: unknown format %s, expected text or json\n", *format)
+		return
+	}
+	file := func(path string) string {
+		if len(args) > 1 {
+			return path
+		}
+		return ""
+	}
+	fail := func(path string, err error) {
+		if *format == "json" {
+			functions.WriteJSONFileError(os.Stdout, file(path), err)
+		} else {
+			fmt.Println(err)
+		}
+	}
+	var eventWriter *functions.EventWriter
+	if *events != "" {
+		eventsFile, err := os.Create(*events)
+		if err != nil {
+			fail("", fmt.Errorf("ERROR: failed to create events file: %v", err))
+			return
+		}
+		defer eventsFile.Close()
+		eventWriter = functions.NewEventWriter(eventsFile)
+	}
+	failed := false
+	for i, path := range args {
+		if len(args) > 1 && *format == "text" {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("==> %s <==\n", path)
+		}
+		var onEvent func(entities.Event)
+		if eventWriter != nil {
+			onEvent = func(event entities.Event) {
+				event.File = file(path)
+				eventWriter.Write(event)
+			}
+		}
+		Colony, schedule, err := solveFile(path, *solverName, *tunnels, *strictness, onEvent)
 		if err != nil {
-			errors = append(errors, err.Error())
+			fail(path, err)
+			failed = true
+			continue
+		}
+		if eventWriter != nil && eventWriter.Err != nil {
+			fail(path, fmt.Errorf("ERROR: failed to write events file: %v", eventWriter.Err))
+			os.Exit(1)
+		}
+		if *explain {
+			functions.PrintExplanation(Colony, schedule)
 			continue
 		}
-		shortestPaths = append(shortestPaths, path)
+		if *format == "json" {
+			if err := functions.WriteJSONFile(os.Stdout, file(path), Colony, schedule); err != nil {
+				fail(path, fmt.Errorf("ERROR: failed to write JSON output: %v", err))
+				os.Exit(1)
+			}
+			continue
+		}
+		for _, line := range Colony.Text {
+			fmt.Println(line)
+		}
+		fmt.Println()
+		functions.PrintMovements(schedule)
 	}
-	if len(errors) == len(graph.GetVertex(Start).Adjacent) {
-		fmt.Println(errors[0])
-		return
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// solveFile parses the colony file at path with the named strictness, applies the named tunnel rule and routes the ants
+// with the named solver, passing every event of the simulation to onEvent when it isn't nil.
+// The parser's warnings are printed to the standard error so that they don't mix with the moves.
+// It returns the colony and the schedule of its ants, or the first error met.
+func solveFile(path, solverName, tunnels, strictness string, onEvent func(entities.Event)) (*functions.Colony, *entities.Schedule, error) {
+	solver, ok := functions.GetSolver(solverName)
+	if !ok {
+		return nil, nil, fmt.Errorf("ERROR: unknown solver %s, expected one of: %s", solverName, strings.Join(functions.SolverNames(), ", "))
+	}
+	strictInput, err := parseStrictness(strictness)
+	if err != nil {
+		return nil, nil, err
+	}
+	Colony, err := functions.ParseFileOptions(path, functions.ParseOptions{KeepText: true, Strictness: strictInput})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, warning := range Colony.Warnings {
+		fmt.Fprintf(os.Stderr, "%s:%s: %v\n", path, warning.Position(), warning)
 	}
-	for _, line := range text {
-		fmt.Println(line)
+	if Colony.TunnelRule, err = parseTunnelRule(tunnels); err != nil {
+		return nil, nil, err
+	}
+	Colony.OnEvent = onEvent
+	schedule, err := solver.Solve(Colony)
+	if err != nil {
+		return nil, nil, err
+	}
+	return Colony, schedule, nil
+}
+
+// checkColony parses the colony file at path in lint mode and prints every error and warning found, one per line,
+// prefixed with its file position, it returns false when the file can't be read or contains errors.
+func checkColony(path string, strictness functions.Strictness) bool {
+	file, err := functions.OpenInput(path)
+	if err != nil {
+		fmt.Printf("ERROR: invalid data format, failed to open file: %v \n", err)
+		return false
+	}
+	defer file.Close()
+	problems := functions.CheckColonyOptions(file, functions.ParseOptions{Strictness: strictness})
+	for _, problem := range problems {
+		if problem.Line == 0 {
+			fmt.Printf("%s: %v\n", path, problem)
+		} else {
+			fmt.Printf("%s:%s: %v\n", path, problem.Position(), problem)
+		}
+	}
+	return !functions.HasErrors(problems)
+}
+
+// verifyCommand implements "lem-in verify <map> <moves>": it replays the transcript in the moves file against the colony
+// in the map file and prints the first rule violation, exiting with status 1, or a summary of the legal transcript.
+func verifyCommand(args []string) {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	tunnels := flags.String("tunnels", "strict", "tunnel rule the transcript follows: strict or lenient")
+	strictness := flags.String("strictness", "strict", "map strictness: strict or relaxed")
+	flags.Parse(args)
+	args = flags.Args()
+	if len(args) != 2 {
+		fmt.Println("ERROR: expected two arguments (map file, moves file)")
+		os.Exit(2)
+	}
+	strictInput, err := parseStrictness(*strictness)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	Colony, err := functions.ParseFileOptions(args[0], functions.ParseOptions{Strictness: strictInput})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if Colony.TunnelRule, err = parseTunnelRule(*tunnels); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	file, err := functions.OpenInput(args[1])
+	if err != nil {
+		fmt.Printf("ERROR: failed to open file: %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+	turns, err := verifier.ReadTranscript(file)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	result, err := verifier.Verify(Colony, turns)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("OK: %d ants reached %s in %d turns with %d moves\n", Colony.NumberOfAnts, Colony.End, result.Turns, result.Moves)
+}
+
+// parseTunnelRule converts the value of a --tunnels flag into a TunnelRule.
+func parseTunnelRule(name string) (functions.TunnelRule, error) {
+	switch name {
+	case "strict":
+		return functions.StrictTunnels, nil
+	case "lenient":
+		return functions.LenientTunnels, nil
+	}
+	return functions.StrictTunnels, fmt.Errorf("ERROR: unknown tunnel rule %s, expected strict or lenient", name)
+}
+
+// parseStrictness converts the value of a --strictness flag into a Strictness.
+func parseStrictness(name string) (functions.Strictness, error) {
+	switch name {
+	case "strict":
+		return functions.StrictInput, nil
+	case "relaxed":
+		return functions.RelaxedInput, nil
 	}
-	fmt.Println()
-	shortestPaths = functions.Sort(shortestPaths)
-	shortestPaths = graph.CheckShortestPaths(shortestPaths, Start, End)
-	pathCombinations := graph.GetPathCombinations(shortestPaths, &Colony)
-	pathCombinations = functions.CleanDuplicatedCombinations(pathCombinations, &Colony)
-	movements := functions.DeployAntArmy(pathCombinations, &Colony)
-	functions.PrintMovements(movements)
+	return functions.StrictInput, fmt.Errorf("ERROR: unknown strictness %s, expected strict or relaxed", name)
 }
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"lem-in/functions"
+)
+
+// renderCommand implements "lem-in render --svg|--gif|--dot [-o file] <map>": it solves the colony and draws it with the paths
+// of the chosen combination (SVG), as an animated replay of the moves (GIF) or exports it as a Graphviz graph (DOT),
+// writing the result to the output file or to the standard output.
+func renderCommand(args []string) {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	svg := flags.Bool("svg", false, "render the colony and its paths as an SVG image")
+	gifReplay := flags.Bool("gif", false, "render an animated GIF replay of the ant movement")
+	frames := flags.Int("frames", 4, "frames drawn for each turn of the GIF replay, the ants slide between rooms over them (lowered for long replays)")
+	dot := flags.Bool("dot", false, "export the colony as a Graphviz DOT graph")
+	dotEnds := flags.Bool("dot-ends", true, "DOT: mark the start and end rooms")
+	dotPaths := flags.Bool("dot-paths", true, "DOT: colour the chosen vertex-disjoint paths")
+	dotLabels := flags.Bool("dot-labels", true, "DOT: label tunnels with the number of ants going through them")
+	dotPin := flags.Bool("dot-pin", false, "DOT: pin rooms at their coordinates (for neato -n or fdp)")
+	output := flags.String("o", "", "output file (default: standard output)")
+	solverName := flags.String("solver", "default", "path selection algorithm")
+	tunnels := flags.String("tunnels", "strict", "tunnel rule: strict or lenient")
+	strictness := flags.String("strictness", "strict", "input strictness: strict or relaxed")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Println("ERROR: expected one argument (file name)")
+		os.Exit(2)
+	}
+	if formats := btoi(*svg) + btoi(*gifReplay) + btoi(*dot); formats != 1 {
+		fmt.Println("ERROR: expected one output format, --svg, --gif or --dot")
+		os.Exit(2)
+	}
+	Colony, schedule, err := solveFile(flags.Arg(0), *solverName, *tunnels, *strictness, nil)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	err = writeOutput(*output, func(w io.Writer) error {
+		if *gifReplay {
+			return functions.WriteGIF(w, Colony, schedule, *frames)
+		}
+		if *dot {
+			return functions.WriteDOT(w, Colony, schedule, functions.DOTOptions{MarkEnds: *dotEnds, Paths: *dotPaths, EdgeLabels: *dotLabels, PinPositions: *dotPin})
+		}
+		return functions.WriteSVG(w, Colony, schedule)
+	})
+	if err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// writeOutput calls write with the file at path, created or truncated, or with the standard output when path is empty.
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// btoi returns 1 for true and 0 for false, to count the flags that are set.
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"lem-in/functions"
+	"lem-in/viz"
+)
+
+// serveCommand implements "lem-in serve --viz [-addr host:port] <map>": it solves the colony and serves the embedded
+// web visualizer for it locally, everything (page, script and data) being served by this process.
+// Colonies loaded from the page are read with the same strictness and routed with the same tunnel rule.
+func serveCommand(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	vizMode := flags.Bool("viz", false, "serve the web visualizer")
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	solverName := flags.String("solver", "default", "path selection algorithm")
+	tunnels := flags.String("tunnels", "strict", "tunnel rule: strict or lenient")
+	strictness := flags.String("strictness", "strict", "input strictness: strict or relaxed")
+	flags.Parse(args)
+	if flags.NArg() != 1 || !*vizMode {
+		fmt.Println("ERROR: usage: lem-in serve --viz [-addr host:port] <map>")
+		os.Exit(2)
+	}
+	Colony, schedule, err := solveFile(flags.Arg(0), *solverName, *tunnels, *strictness, nil)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	strictInput, _ := parseStrictness(*strictness)
+	fmt.Printf("Serving the visualizer for %s on http://%s/\n", flags.Arg(0), *addr)
+	if err := http.ListenAndServe(*addr, viz.Handler(Colony, schedule, functions.ParseOptions{Strictness: strictInput})); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
+	}
+}
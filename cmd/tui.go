@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"lem-in/functions"
+)
+
+// tuiCommand implements "lem-in tui [-width w] [-height h] [-auto] <map>": it solves the colony and draws it as ASCII,
+// then steps through the turns on keypress: space, enter, n or l go forward, b or h go back, g and G jump to the first
+// and last turn, and q quits. When the terminal can't be switched to unbuffered input, each key is followed by enter.
+// The keys being read from the standard input, the colony can't be, and the drawing is at least 2x2.
+// The terminal settings are restored on exit, including when the process is interrupted or terminated.
+func tuiCommand(args []string) {
+	flags := flag.NewFlagSet("tui", flag.ExitOnError)
+	width := flags.Int("width", 78, "width of the drawing in characters")
+	height := flags.Int("height", 24, "height of the drawing in lines")
+	auto := flags.Bool("auto", false, "lay the rooms out by distance from the start room instead of using their coordinates")
+	solverName := flags.String("solver", "default", "path selection algorithm")
+	tunnels := flags.String("tunnels", "strict", "tunnel rule: strict or lenient")
+	strictness := flags.String("strictness", "strict", "input strictness: strict or relaxed")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Println("ERROR: expected one argument (file name)")
+		os.Exit(2)
+	}
+	if flags.Arg(0) == "-" {
+		fmt.Println("ERROR: the tui reads its keys from the standard input, the colony must be read from a file")
+		os.Exit(2)
+	}
+	if *width < 2 || *height < 2 {
+		fmt.Printf("ERROR: invalid drawing size %dx%d, expected a width and a height of at least 2\n", *width, *height)
+		os.Exit(2)
+	}
+	Colony, schedule, err := solveFile(flags.Arg(0), *solverName, *tunnels, *strictness, nil)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	asciiMap := functions.NewASCIIMap(Colony, *width, *height, *auto)
+	restore := unbufferInput()
+	defer restore()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		<-signals
+		restore()
+		fmt.Println()
+		os.Exit(130)
+	}()
+	reader := bufio.NewReader(os.Stdin)
+	turn := 0
+	for {
+		fmt.Print("\x1b[H\x1b[2J")
+		fmt.Print(asciiMap.Render(schedule, turn, true))
+		fmt.Print("[space/n] next  [b] back  [g/G] first/last  [q] quit ")
+		key, _, err := reader.ReadRune()
+		if err != nil {
+			fmt.Println()
+			return
+		}
+		switch key {
+		case ' ', '\n', '\r', 'n', 'l':
+			turn = min(turn+1, len(schedule.Turns))
+		case 'b', 'h':
+			turn = max(turn-1, 0)
+		case 'g':
+			turn = 0
+		case 'G':
+			turn = len(schedule.Turns)
+		case 'q':
+			fmt.Println()
+			return
+		}
+	}
+}
+
+// unbufferInput switches the terminal to character-at-a-time input without echo using stty,
+// it returns the function restoring the previous settings, which does nothing if stty isn't available.
+func unbufferInput() func() {
+	saved, err := stty("-g")
+	if err != nil {
+		return func() {}
+	}
+	if _, err := stty("cbreak", "-echo"); err != nil {
+		return func() {}
+	}
+	return func() {
+		stty(strings.TrimSpace(saved))
+	}
+}
+
+// stty runs the stty command on the terminal of the standard input and returns its output.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	output, err := cmd.Output()
+	return string(output), err
+}
@@ -1,13 +1,30 @@
 package entities
 
-// Graph struct holds a list of vertices, where each vertex represents a point in the graph.
+// Graph struct holds a list of vertices, where each vertex represents a point in the graph,
+// an index from vertex key to vertex so that lookups by name don't scan the whole list,
+// the edges (tunnels) in declaration order, and the comment lines at the end of the input that aren't attached to a room or a tunnel.
 type Graph struct {
 	Vertices []*Vertex
+	Index    map[string]*Vertex
+	Edges    []*Edge
+	Comments []string
 }
 
-// Vertex struct contains a key (identifier) and a list of adjacent vertices (neighbors).
+// Edge struct is a tunnel between the rooms From and To as it was declared, with the comment lines written right before it.
+type Edge struct {
+	From     string
+	To       string
+	Comments []string
+}
+
+// Vertex struct contains an integer identifier (its position in Graph.Vertices), a key (name), the X and Y coordinates of the room,
+// the comment and command lines (such as ##start) written right before the room, and a list of adjacent vertices (neighbors).
 type Vertex struct {
+	Id       int
 	Key      string
+	X        int
+	Y        int
+	Comments []string
 	Adjacent []*Vertex
 }
 
@@ -18,3 +35,50 @@ type Ant struct {
 	Position  int
 	Finished  bool
 }
+
+// Move struct records one ant going from a room to an adjacent one during a turn (turns are numbered from 1).
+type Move struct {
+	Ant  int
+	From string
+	To   string
+	Turn int
+}
+
+// Event struct is one step of a simulation as reported to an event log: the type of the event (turn_start, ant_spawn,
+// ant_move, ant_arrive or turn_end), the turn it happens in, and depending on the type the ant, the rooms it moves between,
+// the 1-based path it was sent on (ant_spawn) and the number of moves of the turn (turn_end).
+// File is set by the caller to the colony file the event comes from when several are simulated into one log.
+type Event struct {
+	File  string `json:"file,omitempty"`
+	Type  string `json:"type"`
+	Turn  int    `json:"turn"`
+	Ant   int    `json:"ant,omitempty"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+	Path  int    `json:"path,omitempty"`
+	Moves int    `json:"moves,omitempty"`
+}
+
+// Candidate struct describes one path combination considered by the deployment: its paths (without the start room),
+// the number of ants planned on each path, the predicted number of turns and total steps, whether it was chosen
+// and the reason it won or lost against the chosen one.
+type Candidate struct {
+	Paths       [][]string
+	AntsPerPath []int
+	Turns       int
+	Steps       int
+	Chosen      bool
+	Reason      string
+}
+
+// Schedule struct holds the result of a deployment: the moves of every turn, the paths the ants were sent on
+// (each starting with the start room), the ids of the ants assigned to each path, the number of turns and moves,
+// and the candidate combinations the paths were chosen from.
+type Schedule struct {
+	Turns      [][]Move
+	Paths      [][]string
+	PathAnts   [][]int
+	TurnCount  int
+	TotalMoves int
+	Candidates []Candidate
+}
@@ -0,0 +1,166 @@
+package functions
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+)
+
+// jsonColonyInput is the document read by parseJSON, jsonColony with coordinates that are pointers
+// so that a room without x or y can be told from a room at 0.
+type jsonColonyInput struct {
+	Ants    int             `json:"ants"`
+	Start   string          `json:"start"`
+	End     string          `json:"end"`
+	Rooms   []jsonRoomInput `json:"rooms"`
+	Tunnels [][]string      `json:"tunnels"`
+}
+
+type jsonRoomInput struct {
+	Name     string   `json:"name"`
+	X        *int     `json:"x"`
+	Y        *int     `json:"y"`
+	Comments []string `json:"comments,omitempty"`
+}
+
+// isJSONInput peeks at the buffered input and reports whether its first non-blank character opens a JSON object,
+// a lem-in text colony always starts with its ant count.
+func isJSONInput(reader *bufio.Reader) bool {
+	for n := 1; n <= reader.Size(); n++ {
+		peeked, _ := reader.Peek(n)
+		if len(peeked) < n {
+			return false
+		}
+		switch peeked[n-1] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '{':
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// parseJSON decodes a colony written as {"ants", "start", "end", "rooms": [{"name", "x", "y"}], "tunnels": [[a, b]]}
+// and builds it with the same checks as the text format: a positive ant count, room names that don't start with L or #
+// and hold no whitespace, no duplicated room or tunnel, no tunnel from a room to itself or to an unknown room,
+// and start and end rooms that exist. Every room needs its x and y, every tunnel is a pair of room names,
+// and rooms may also carry their "comments" (lines starting with #), as written by WriteColonyJSON. Unknown fields and anything following the object are rejected as invalid JSON.
+// In collect mode the errors are recorded and the checks go on, otherwise the first error is returned.
+func (p *colonyParser) parseJSON(r io.Reader) *ParseError {
+	var document jsonColonyInput
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&document); err != nil {
+		return p.report(newParseError(ErrInvalidJSON, "invalid JSON colony: %v", err))
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return p.report(newParseError(ErrInvalidJSON, "invalid JSON colony: unexpected data after the colony object"))
+	}
+	if document.Ants < 1 {
+		if err := p.report(newParseError(ErrInvalidAntCount, "invalid number of Ants")); err != nil {
+			return err
+		}
+	}
+	p.NumberOfAnts = document.Ants
+	for _, room := range document.Rooms {
+		var err *ParseError
+		if room.Name == "" || strings.ContainsAny(room.Name, " \t\r\n") {
+			err = newParseError(ErrInvalidRoom, "invalid room format: %q", room.Name)
+		} else if room.Name[0] == 'L' || room.Name[0] == '#' {
+			err = newParseError(ErrInvalidRoomName, "room shouldn't start with L or #: %s", room.Name)
+		} else if room.X == nil || room.Y == nil {
+			err = newParseError(ErrInvalidCoordinates, "missing coordinates for room %s", room.Name)
+		} else if addErr := p.graph.AddVertex(room.Name); addErr != nil {
+			err = addErr.(*ParseError)
+		}
+		if err != nil {
+			if err := p.report(err.at(0, 0, room.Name)); err != nil {
+				return err
+			}
+			continue
+		}
+		vertex := p.graph.GetVertex(room.Name)
+		vertex.X, vertex.Y, vertex.Comments = *room.X, *room.Y, room.Comments
+		if i := slices.IndexFunc(room.Comments, func(comment string) bool { return !isComment(comment) }); i >= 0 {
+			err := newParseError(ErrInvalidLine, "invalid comment for room %s, expected a line starting with #: %q", room.Name, room.Comments[i])
+			if err := p.report(err.at(0, 0, room.Name)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, tunnel := range document.Tunnels {
+		var err *ParseError
+		if len(tunnel) != 2 {
+			if err := p.report(newParseError(ErrInvalidTunnel, "invalid tunnel format, expected two room names: %q", tunnel).at(0, 0, strings.Join(tunnel, "-"))); err != nil {
+				return err
+			}
+			continue
+		}
+		if tunnel[0] == tunnel[1] {
+			err = newParseError(ErrCircularTunnel, "Circular tunnel not allowed: %s-%s", tunnel[0], tunnel[1])
+		} else if addErr := p.graph.AddEdge(tunnel[0], tunnel[1]); addErr != nil {
+			err = addErr.(*ParseError)
+		}
+		if err != nil {
+			if err := p.report(err.at(0, 0, tunnel[0]+"-"+tunnel[1])); err != nil {
+				return err
+			}
+		}
+	}
+	if document.Start == "" {
+		if err := p.report(newParseError(ErrMissingStart, "missing start room")); err != nil {
+			return err
+		}
+	} else if p.graph.GetVertex(document.Start) == nil {
+		if err := p.report(newParseError(ErrUnknownRoom, "room %s don't exist", document.Start).at(0, 0, document.Start)); err != nil {
+			return err
+		}
+	} else {
+		p.Start = document.Start
+	}
+	if document.End == "" {
+		if err := p.report(newParseError(ErrMissingEnd, "missing end room")); err != nil {
+			return err
+		}
+	} else if p.graph.GetVertex(document.End) == nil {
+		if err := p.report(newParseError(ErrUnknownRoom, "room %s don't exist", document.End).at(0, 0, document.End)); err != nil {
+			return err
+		}
+	} else {
+		p.End = document.End
+	}
+	if p.Start != "" && p.Start == p.End {
+		return p.report(newParseError(ErrMultipleEnd, "the start room %s can't also be the end room", p.Start))
+	}
+	return nil
+}
+
+// WriteColonyJSON writes the colony as one indented JSON document in the format read by ParseColony:
+// the ant count, the start and end rooms, the rooms in declaration order with their coordinates and comments
+// (without ##start and ##end, given by the start and end fields),
+// and the tunnels in declaration order. Tunnel comments and the comments ending a text file have no place in it.
+func WriteColonyJSON(w io.Writer, Colony *Colony) error {
+	document := jsonColony{
+		Ants:    Colony.NumberOfAnts,
+		Start:   Colony.Start,
+		End:     Colony.End,
+		Rooms:   []jsonRoom{},
+		Tunnels: [][2]string{},
+	}
+	for _, vertex := range Colony.Graph.Vertices {
+		comments := slices.DeleteFunc(slices.Clone(vertex.Comments), func(comment string) bool {
+			return comment == "##start" || comment == "##end"
+		})
+		document.Rooms = append(document.Rooms, jsonRoom{Name: vertex.Key, X: vertex.X, Y: vertex.Y, Comments: comments})
+	}
+	for _, edge := range Colony.Graph.Edges {
+		document.Tunnels = append(document.Tunnels, [2]string{edge.From, edge.To})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
@@ -0,0 +1,43 @@
+package functions
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestParseJSONColony checks that valid JSON colonies are accepted with their lem-in text and that malformed ones
+// are rejected with the expected kind of error.
+func TestParseJSONColony(t *testing.T) {
+	const rooms = `"rooms": [{"name": "a", "x": 0, "y": 0}, {"name": "b-c", "x": 1, "y": 0}, {"name": "a-b", "x": 2, "y": 0}, {"name": "c", "x": 3, "y": 0}]`
+	valid := `{"ants": 2, "start": "a", "end": "c", ` + rooms + `, "tunnels": [["a", "b-c"], ["b-c", "c"]]}`
+	Colony, err := ParseColony(strings.NewReader(valid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := strings.Join(Colony.Text, "\n"); !strings.Contains(text, "\nb-c-a\n") {
+		t.Errorf("tunnel a-b-c isn't written the unambiguous way:\n%s", text)
+	}
+	invalid := map[string]struct {
+		input string
+		kind  ErrorKind
+	}{
+		"CommentWithoutHash": {strings.Replace(valid, `"y": 0}`, `"y": 0, "comments": ["hello"]}`, 1), ErrInvalidLine},
+		"LongTunnel":         {strings.Replace(valid, `["a", "b-c"]`, `["a", "b-c", "zzz"]`, 1), ErrInvalidTunnel},
+		"ShortTunnel":        {strings.Replace(valid, `["a", "b-c"]`, `["a"]`, 1), ErrInvalidTunnel},
+		"MissingCoordinate":  {strings.Replace(valid, `"x": 1, `, "", 1), ErrInvalidCoordinates},
+		"UnknownField":       {strings.Replace(valid, `"ants"`, `"antz"`, 1), ErrInvalidJSON},
+		"TrailingData":       {valid + " {}", ErrInvalidJSON},
+	}
+	for name, test := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseColony(strings.NewReader(test.input))
+			if !errors.Is(err, test.kind) {
+				t.Errorf("got %v, want an error of kind %v", err, test.kind)
+			}
+			if problems := CheckColony(strings.NewReader(test.input)); !HasErrors(problems) {
+				t.Errorf("CheckColony reports no error")
+			}
+		})
+	}
+}
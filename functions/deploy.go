@@ -2,37 +2,46 @@ package functions
 
 import (
 	"fmt"
+	"sort"
 
 	"lem-in/entities"
 )
 
 // DeployAntInCombination function manages the movement of ants through various paths.
 // It initializes ants with ID, position, and path, and handles their movement until all ants reach the end.
-// The function checks if a room is occupied, moves ants step by step, and updates their positions.
-// It collects and returns the movements of ants as they proceed through their respective paths.
-func DeployAntInCombination(Colony *Colony, paths [][]string,pathLimits []int) [][]string {
+// The function checks if a room is occupied and, unless the colony uses LenientTunnels, if the tunnel was already used this turn,
+// moves ants step by step, and updates their positions.
+// It collects the movements of ants as they proceed through their respective paths and returns them as a Schedule,
+// reporting each turn and each move to Colony.OnEvent when it is set.
+func DeployAntInCombination(Colony *Colony, paths [][]string, pathLimits []int) *entities.Schedule {
 	var ants []*entities.Ant
-	results := [][]string{}
+	schedule := &entities.Schedule{Paths: paths, PathAnts: make([][]int, len(paths))}
 	for i := 1; i <= Colony.NumberOfAnts; i++ {
 		ants = append(ants, &entities.Ant{Id: i, PathIndex: -1, Position: -1, Finished: false})
 	}
 	finished := 0
 	for finished < Colony.NumberOfAnts {
-		// fmt.Println(pathLimits)
-		movements := []string{}
+		movements := []entities.Move{}
+		turn := len(schedule.Turns) + 1
+		Colony.emit(entities.Event{Type: "turn_start", Turn: turn})
 		occupiedRooms := make(map[string]bool)
+		usedTunnels := make(map[[2]string]bool)
 		for i := range ants {
 			if !ants[i].Finished && ants[i].PathIndex != -1 && ants[i].Position < len(paths[ants[i].PathIndex])-1 {
+				room := paths[ants[i].PathIndex][ants[i].Position]
 				nextRoom := paths[ants[i].PathIndex][ants[i].Position+1]
-				if !occupiedRooms[nextRoom] || nextRoom == Colony.End {
+				if (!occupiedRooms[nextRoom] || nextRoom == Colony.End) && Colony.canUseTunnel(usedTunnels, room, nextRoom) {
 					ants[i].Position++
-					movements = append(movements, fmt.Sprintf("L%d-%s", ants[i].Id, nextRoom))
+					movements = append(movements, entities.Move{Ant: ants[i].Id, From: room, To: nextRoom, Turn: turn})
+					Colony.emit(entities.Event{Type: "ant_move", Turn: turn, Ant: ants[i].Id, From: room, To: nextRoom})
 					if nextRoom != Colony.End {
 						occupiedRooms[nextRoom] = true
 					}
+					usedTunnels[TunnelKey(room, nextRoom)] = true
 					if nextRoom == Colony.End {
 						ants[i].Finished = true
 						finished++
+						Colony.emit(entities.Event{Type: "ant_arrive", Turn: turn, Ant: ants[i].Id, To: nextRoom})
 					}
 				}
 			}
@@ -40,58 +49,108 @@ func DeployAntInCombination(Colony *Colony, paths [][]string,pathLimits []int) [
 		for i := range ants {
 			if ants[i].PathIndex == -1 && ants[i].Position == -1 {
 				for j, path := range paths {
-					if pathLimits[j] > 0 && !occupiedRooms[path[1]] {
+					if pathLimits[j] > 0 && (!occupiedRooms[path[1]] || path[1] == Colony.End) && Colony.canUseTunnel(usedTunnels, path[0], path[1]) {
 						ants[i].PathIndex = j
 						ants[i].Position = 1
-						movements = append(movements, fmt.Sprintf("L%d-%s", ants[i].Id, path[1]))
-						occupiedRooms[path[1]] = true
+						movements = append(movements, entities.Move{Ant: ants[i].Id, From: path[0], To: path[1], Turn: turn})
+						Colony.emit(entities.Event{Type: "ant_spawn", Turn: turn, Ant: ants[i].Id, From: path[0], To: path[1], Path: j + 1})
+						schedule.PathAnts[j] = append(schedule.PathAnts[j], ants[i].Id)
+						if path[1] != Colony.End {
+							occupiedRooms[path[1]] = true
+						}
+						usedTunnels[TunnelKey(path[0], path[1])] = true
 						pathLimits[j]--
 						if path[1] == Colony.End {
 							ants[i].Finished = true
 							finished++
+							Colony.emit(entities.Event{Type: "ant_arrive", Turn: turn, Ant: ants[i].Id, To: path[1]})
 						}
 						break
 					}
 				}
 			}
 		}
+		Colony.emit(entities.Event{Type: "turn_end", Turn: turn, Moves: len(movements)})
 		if len(movements) > 0 {
-			results = append(results, movements)
+			schedule.Turns = append(schedule.Turns, movements)
+			schedule.TotalMoves += len(movements)
 		}
 	}
-	return results
+	schedule.TurnCount = len(schedule.Turns)
+	return schedule
 }
 
 // DeployAntArmy function manages the deployment of an ant army across different path combinations.
-// It prepares each path by appending the start room, sorts the paths, and then uses DeployAntInCombination to handle the actual movement.
-// After all paths are processed, the function compares the results and returns the best solution for the ant deployment.
-func DeployAntArmy(pathCombinations map[int][][]string, Colony *Colony) [][]string {
-	pathLimits := map[int][]int{}
-	index := 0
-	minSteps := 0
-	for key, pathCombination := range pathCombinations {
-		pathLimits[key] = calculatePathLimits(pathCombination, Colony.NumberOfAnts)
+// It evaluates every combination with EvaluateCombinations, prepares the paths of the best one by appending the start room,
+// and then uses DeployAntInCombination to handle the actual movement.
+// It returns the schedule of the best solution for the ant deployment, along with the evaluated candidates.
+func DeployAntArmy(pathCombinations map[int][][]string, Colony *Colony) *entities.Schedule {
+	candidates, index := EvaluateCombinations(pathCombinations, Colony)
+	paths := [][]string{}
+	for _, path := range candidates[index].Paths {
+		newPath := []string{Colony.Start}
+		newPath = append(newPath, path...)
+		paths = append(paths, newPath)
 	}
-	minTurns := pathLimits[0][0] + len(pathCombinations[0][0]) - 1
-	minSteps = GetNumberOfSteps(pathLimits[0], pathCombinations[0])
-	for i, pathCombination := range pathCombinations {
-		if pathLimits[i][0]+len(pathCombination[0])-1 == minTurns {
-			checkMinSteps := GetNumberOfSteps(pathLimits[i], pathCombination)
-			if minSteps > checkMinSteps {
-				minSteps = checkMinSteps
-				index = i
-			}
-		} else if pathLimits[i][0]+len(pathCombination[0])-1 < minTurns {
-			minTurns = pathLimits[i][0] + len(pathCombination[0]) - 1
-			minSteps = GetNumberOfSteps(pathLimits[i], pathCombination)
+	result := DeployAntInCombination(Colony, paths, append([]int{}, candidates[index].AntsPerPath...))
+	result.Candidates = candidates
+	return result
+}
+
+// EvaluateCombinations computes the ants per path, the number of turns and the total steps of every path combination,
+// in the order of their keys, and picks the combination needing the fewest turns, then the fewest steps, then the one with
+// the smallest key. With keys that don't depend on map iteration order, as given by CleanDuplicatedCombinations,
+// the candidates and the chosen one are the same on every run.
+// It returns the candidates, each with the reason it was chosen or beaten, and the position of the chosen one.
+func EvaluateCombinations(pathCombinations map[int][][]string, Colony *Colony) ([]entities.Candidate, int) {
+	keys := []int{}
+	for key := range pathCombinations {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	candidates := []entities.Candidate{}
+	index := 0
+	for i, key := range keys {
+		pathCombination := pathCombinations[key]
+		limits := Colony.calculatePathLimits(pathCombination)
+		candidates = append(candidates, entities.Candidate{
+			Paths:       append([][]string{}, pathCombination...),
+			AntsPerPath: limits,
+			Turns:       Colony.CombinationTurns(pathCombination, limits),
+			Steps:       GetNumberOfSteps(limits, pathCombination),
+		})
+		if candidates[i].Turns < candidates[index].Turns || (candidates[i].Turns == candidates[index].Turns && candidates[i].Steps < candidates[index].Steps) {
 			index = i
 		}
 	}
-	for i, path := range pathCombinations[index] {
-		newPath := []string{Colony.Start}
-		newPath = append(newPath, path...)
-		pathCombinations[index][i] = newPath
+	best := &candidates[index]
+	best.Chosen = true
+	moreTurns, moreSteps, ties := false, false, false
+	for i := range candidates {
+		candidate := &candidates[i]
+		switch {
+		case i == index:
+			continue
+		case candidate.Turns > best.Turns:
+			candidate.Reason = fmt.Sprintf("needs %d turns, %d more than the chosen one", candidate.Turns, candidate.Turns-best.Turns)
+			moreTurns = true
+		case candidate.Steps > best.Steps:
+			candidate.Reason = fmt.Sprintf("same %d turns but %d total steps, %d more than the chosen one", candidate.Turns, candidate.Steps, candidate.Steps-best.Steps)
+			moreSteps = true
+		default:
+			candidate.Reason = fmt.Sprintf("same %d turns and %d total steps, the chosen one comes first", candidate.Turns, candidate.Steps)
+			ties = true
+		}
 	}
-	result := DeployAntInCombination(Colony,pathCombinations[index],pathLimits[index])
-	return result
+	switch {
+	case ties:
+		best.Reason = fmt.Sprintf("fewest turns (%d) and fewest total steps (%d), first of the equally good candidates", best.Turns, best.Steps)
+	case moreSteps:
+		best.Reason = fmt.Sprintf("fewest turns (%d) and fewest total steps (%d) among the candidates with that many turns", best.Turns, best.Steps)
+	case moreTurns:
+		best.Reason = fmt.Sprintf("fewest turns (%d)", best.Turns)
+	default:
+		best.Reason = "only candidate"
+	}
+	return candidates, index
 }
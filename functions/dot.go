@@ -0,0 +1,85 @@
+package functions
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"lem-in/entities"
+)
+
+// DOTOptions selects what WriteDOT adds on top of the rooms and tunnels: MarkEnds styles the start and end rooms,
+// Paths colours the paths of the schedule, EdgeLabels labels each tunnel with the number of ants going through it
+// (and draws busier tunnels thicker), and PinPositions fixes every room at its coordinates from the input file.
+// Paths and EdgeLabels need a schedule.
+type DOTOptions struct {
+	MarkEnds     bool
+	Paths        bool
+	EdgeLabels   bool
+	PinPositions bool
+}
+
+// WriteDOT writes the network of the colony as an undirected Graphviz graph, decorated with the schedule according to opts,
+// schedule may be nil when only the network is wanted.
+func WriteDOT(w io.Writer, Colony *Colony, schedule *entities.Schedule, opts DOTOptions) error {
+	graph := Colony.Graph
+	traffic := map[[2]string]int{}
+	pathOf := map[[2]string]int{}
+	busiest := 0
+	if schedule != nil {
+		for _, turn := range schedule.Turns {
+			for _, move := range turn {
+				key := TunnelKey(move.From, move.To)
+				traffic[key]++
+				busiest = max(busiest, traffic[key])
+			}
+		}
+		for i, path := range schedule.Paths {
+			for j := 1; j < len(path); j++ {
+				pathOf[TunnelKey(path[j-1], path[j])] = i + 1
+			}
+		}
+	}
+	var b strings.Builder
+	b.WriteString("graph colony {\n")
+	b.WriteString("  node [shape=circle, fontsize=10];\n")
+	for _, vertex := range graph.Vertices {
+		attributes := []string{}
+		if opts.MarkEnds && vertex.Key == Colony.Start {
+			attributes = append(attributes, `style=filled`, `fillcolor="#2ca02c"`, `xlabel="start"`)
+		} else if opts.MarkEnds && vertex.Key == Colony.End {
+			attributes = append(attributes, `style=filled`, `fillcolor="#d62728"`, `xlabel="end"`)
+		}
+		if opts.PinPositions {
+			attributes = append(attributes, fmt.Sprintf(`pos="%d,%d!"`, vertex.X, -vertex.Y))
+		}
+		fmt.Fprintf(&b, "  %s", dotQuote(vertex.Key))
+		if len(attributes) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attributes, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	for _, tunnel := range graph.Tunnels() {
+		key := TunnelKey(tunnel[0], tunnel[1])
+		attributes := []string{}
+		if opts.Paths && pathOf[key] > 0 {
+			attributes = append(attributes, fmt.Sprintf(`color="%s"`, PathColor(pathOf[key]-1)))
+		}
+		if opts.EdgeLabels && traffic[key] > 0 {
+			attributes = append(attributes, fmt.Sprintf(`label="%d"`, traffic[key]), fmt.Sprintf(`penwidth=%.1f`, 1+4*float64(traffic[key])/float64(busiest)))
+		}
+		fmt.Fprintf(&b, "  %s -- %s", dotQuote(tunnel[0]), dotQuote(tunnel[1]))
+		if len(attributes) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attributes, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote returns the name as a quoted DOT identifier.
+func dotQuote(name string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(name, `\`, `\\`), `"`, `\"`) + `"`
+}
@@ -0,0 +1,128 @@
+package functions
+
+import "fmt"
+
+// ErrorKind identifies the category of a parse failure, it implements error so that every ParseError
+// can be matched against its kind with errors.Is.
+type ErrorKind int
+
+const (
+	ErrReadInput ErrorKind = iota + 1
+	ErrInvalidJSON
+	ErrEmptyFile
+	ErrInvalidAntCount
+	ErrInvalidLine
+	ErrInvalidRoom
+	ErrInvalidRoomName
+	ErrInvalidCoordinates
+	ErrDuplicateRoom
+	ErrInvalidTunnel
+	ErrCircularTunnel
+	ErrUnknownRoom
+	ErrDuplicateTunnel
+	ErrAmbiguousTunnel
+	ErrMultipleStart
+	ErrMultipleEnd
+	ErrMissingStart
+	ErrMissingEnd
+	ErrNoPath
+	WarnIsolatedRoom
+	WarnDeadEndRoom
+	WarnSharedCoordinates
+	WarnUnknownCommand
+)
+
+var errorKindNames = map[ErrorKind]string{
+	ErrReadInput:          "read input",
+	ErrInvalidJSON:        "invalid json",
+	ErrEmptyFile:          "empty file",
+	ErrInvalidAntCount:    "invalid ant count",
+	ErrInvalidLine:        "invalid line",
+	ErrInvalidRoom:        "invalid room",
+	ErrInvalidRoomName:    "invalid room name",
+	ErrInvalidCoordinates: "invalid coordinates",
+	ErrDuplicateRoom:      "duplicate room",
+	ErrInvalidTunnel:      "invalid tunnel",
+	ErrCircularTunnel:     "circular tunnel",
+	ErrUnknownRoom:        "unknown room",
+	ErrDuplicateTunnel:    "duplicate tunnel",
+	ErrAmbiguousTunnel:    "ambiguous tunnel",
+	ErrMultipleStart:      "multiple start",
+	ErrMultipleEnd:        "multiple end",
+	ErrMissingStart:       "missing start",
+	ErrMissingEnd:         "missing end",
+	ErrNoPath:             "no path",
+	WarnIsolatedRoom:      "isolated room",
+	WarnDeadEndRoom:       "dead-end room",
+	WarnSharedCoordinates: "shared coordinates",
+	WarnUnknownCommand:    "unknown command",
+}
+
+// String returns the stable, human readable name of the error kind.
+func (k ErrorKind) String() string {
+	if name, ok := errorKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("ErrorKind(%d)", int(k))
+}
+
+// Error returns the name of the error kind so that the kind itself can be used as a target for errors.Is.
+func (k ErrorKind) Error() string {
+	return k.String()
+}
+
+// ParseError describes a failure found while reading a colony, it carries the stable kind of the failure,
+// the 1-based line and column where it was detected (0 when unknown), the offending text and the message
+// printed after the classic "ERROR: invalid data format, " prefix.
+// Warnings reported by CheckColony use the same type with Warning set to true.
+type ParseError struct {
+	Kind    ErrorKind
+	Line    int
+	Column  int
+	Text    string
+	Msg     string
+	Warning bool
+}
+
+// newParseError creates a ParseError of the given kind with a formatted message and no position,
+// the position is filled in with at when the error is bound to an input line.
+func newParseError(kind ErrorKind, format string, args ...any) *ParseError {
+	return &ParseError{Kind: kind, Msg: fmt.Sprintf(format, args...)}
+}
+
+// newWarning creates a ParseError of the given kind flagged as a warning, it doesn't make the colony invalid.
+func newWarning(kind ErrorKind, format string, args ...any) *ParseError {
+	return &ParseError{Kind: kind, Msg: fmt.Sprintf(format, args...), Warning: true}
+}
+
+// at sets the line, column and offending text of the error and returns it for chaining,
+// a position that is already known is kept.
+func (e *ParseError) at(line, column int, text string) *ParseError {
+	if e.Line == 0 {
+		e.Line = line
+		e.Column = column
+		e.Text = text
+	}
+	return e
+}
+
+// Error returns the classic "ERROR: invalid data format, ..." message of the error, or "WARNING: ..." for a warning.
+func (e *ParseError) Error() string {
+	if e.Warning {
+		return "WARNING: " + e.Msg
+	}
+	return "ERROR: invalid data format, " + e.Msg
+}
+
+// Unwrap returns the kind of the error so that errors.Is(err, ErrDuplicateRoom) and the like match.
+func (e *ParseError) Unwrap() error {
+	return e.Kind
+}
+
+// Position returns the "line:column" location of the error, or an empty string when it is not tied to a line.
+func (e *ParseError) Position() string {
+	if e.Line == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", e.Line, e.Column)
+}
@@ -0,0 +1,34 @@
+package functions
+
+import (
+	"encoding/json"
+	"io"
+
+	"lem-in/entities"
+)
+
+// EventWriter writes simulation events as newline-delimited JSON, one object per line,
+// the first write error is kept in Err and the following events are dropped.
+type EventWriter struct {
+	encoder *json.Encoder
+	Err     error
+}
+
+// NewEventWriter creates an EventWriter writing to w, its Write method can be used as Colony.OnEvent.
+func NewEventWriter(w io.Writer) *EventWriter {
+	return &EventWriter{encoder: json.NewEncoder(w)}
+}
+
+// Write encodes the event as one line of JSON.
+func (w *EventWriter) Write(event entities.Event) {
+	if w.Err == nil {
+		w.Err = w.encoder.Encode(event)
+	}
+}
+
+// emit passes the event to the colony's OnEvent function when one is set.
+func (c *Colony) emit(event entities.Event) {
+	if c.OnEvent != nil {
+		c.OnEvent(event)
+	}
+}
@@ -0,0 +1,27 @@
+package functions
+
+import (
+	"fmt"
+	"strings"
+
+	"lem-in/entities"
+)
+
+// PrintExplanation prints every candidate path combination the schedule was chosen from, with its predicted turns,
+// its total steps, the ants planned on each path and the reason it won or lost, followed by the chosen combination.
+func PrintExplanation(Colony *Colony, schedule *entities.Schedule) {
+	chosen := 0
+	for i, candidate := range schedule.Candidates {
+		mark := ""
+		if candidate.Chosen {
+			mark = " [chosen]"
+			chosen = i + 1
+		}
+		fmt.Printf("Candidate %d%s: %d paths, %d turns, %d total steps\n", i+1, mark, len(candidate.Paths), candidate.Turns, candidate.Steps)
+		for j, path := range candidate.Paths {
+			fmt.Printf("  path %d (%d moves, %d ants): %s\n", j+1, len(path), candidate.AntsPerPath[j], strings.Join(append([]string{Colony.Start}, path...), " -> "))
+		}
+		fmt.Printf("  reason: %s\n", candidate.Reason)
+	}
+	fmt.Printf("Chosen: candidate %d of %d, %d turns and %d moves in the schedule\n", chosen, len(schedule.Candidates), schedule.TurnCount, schedule.TotalMoves)
+}
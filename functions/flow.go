@@ -0,0 +1,156 @@
+package functions
+
+import "lem-in/entities"
+
+// flowNetwork is the residual state of a max-flow run on a Network where every room is split into an in node
+// and an out node joined by an edge of capacity 1, so that augmenting paths never share a room.
+// tunnelFlow marks the tunnels used from one room to the other, a room's in->out edge carries flow when a tunnel leads flow into it.
+type flowNetwork struct {
+	start      *entities.Vertex
+	end        *entities.Vertex
+	tunnelFlow map[[2]int]bool
+}
+
+// flowNode is a node of the split graph: the in or out side of a room.
+type flowNode struct {
+	vertex *entities.Vertex
+	out    bool
+}
+
+// GetDisjointPaths finds the set of vertex-disjoint paths from start to end that moves the colony's ants in the fewest turns,
+// picking with EvaluateCombinations among the path sets returned by GetFlowPathSets.
+// The paths don't include the start room and are sorted by length.
+func (g *Network) GetDisjointPaths(Colony *Colony) ([][]string, error) {
+	pathSets, err := g.GetFlowPathSets(Colony)
+	if err != nil {
+		return nil, err
+	}
+	candidates, index := EvaluateCombinations(pathSets, Colony)
+	return candidates[index].Paths, nil
+}
+
+// GetFlowPathSets splits each room into in/out nodes and augments flow one shortest path at a time (Edmonds-Karp),
+// recording the set of vertex-disjoint paths carried by the flow after every augmentation, keyed by augmentation order.
+// It stops when no augmenting path is left or when there are as many paths as ants, and returns an error when start and end aren't connected.
+func (g *Network) GetFlowPathSets(Colony *Colony) (map[int][][]string, error) {
+	flow := &flowNetwork{
+		start:      g.GetVertex(Colony.Start),
+		end:        g.GetVertex(Colony.End),
+		tunnelFlow: make(map[[2]int]bool),
+	}
+	pathSets := map[int][][]string{}
+	for paths := 0; paths < Colony.NumberOfAnts && flow.augment(); paths++ {
+		pathSets[paths] = flow.paths()
+	}
+	if len(pathSets) == 0 {
+		return nil, newParseError(ErrNoPath, "There's no path between start and end")
+	}
+	return pathSets, nil
+}
+
+// augment searches the residual split graph breadth-first for a path from the out node of the start room
+// to the in node of the end room and pushes one unit of flow along it, it returns false when no such path exists.
+func (f *flowNetwork) augment() bool {
+	source := flowNode{f.start, true}
+	parent := map[flowNode]flowNode{source: source}
+	queue := []flowNode{source}
+	found := false
+	for len(queue) > 0 && !found {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range f.residual(node) {
+			if _, seen := parent[next]; seen {
+				continue
+			}
+			parent[next] = node
+			if next.vertex == f.end {
+				found = true
+				break
+			}
+			queue = append(queue, next)
+		}
+	}
+	if !found {
+		return false
+	}
+	for node := (flowNode{f.end, false}); node != source; node = parent[node] {
+		f.push(parent[node], node)
+	}
+	return true
+}
+
+// residual returns the nodes reachable from node through an edge of the residual split graph that still has capacity.
+func (f *flowNetwork) residual(node flowNode) []flowNode {
+	nodes := []flowNode{}
+	v := node.vertex
+	if node.out {
+		if v != f.start && f.carries(v) {
+			nodes = append(nodes, flowNode{v, false})
+		}
+		for _, u := range v.Adjacent {
+			if u != f.start && !f.tunnelFlow[[2]int{v.Id, u.Id}] {
+				nodes = append(nodes, flowNode{u, false})
+			}
+		}
+		return nodes
+	}
+	if !f.carries(v) {
+		nodes = append(nodes, flowNode{v, true})
+	}
+	for _, u := range v.Adjacent {
+		if f.tunnelFlow[[2]int{u.Id, v.Id}] {
+			nodes = append(nodes, flowNode{u, true})
+		}
+	}
+	return nodes
+}
+
+// carries reports whether flow goes through the room v, that is whether a tunnel leads flow into it.
+func (f *flowNetwork) carries(v *entities.Vertex) bool {
+	for _, u := range v.Adjacent {
+		if f.tunnelFlow[[2]int{u.Id, v.Id}] {
+			return true
+		}
+	}
+	return false
+}
+
+// push sends one unit of flow along the residual edge from -> to, cancelling flow on the reverse edge when there is some,
+// and cancelling both directions of a tunnel when they would carry flow at the same time.
+// The flow through a room's own in->out edge follows from its tunnels, so that edge needs no bookkeeping.
+func (f *flowNetwork) push(from, to flowNode) {
+	switch {
+	case from.vertex == to.vertex:
+	case from.out:
+		f.tunnelFlow[[2]int{from.vertex.Id, to.vertex.Id}] = true
+		if f.tunnelFlow[[2]int{to.vertex.Id, from.vertex.Id}] {
+			delete(f.tunnelFlow, [2]int{from.vertex.Id, to.vertex.Id})
+			delete(f.tunnelFlow, [2]int{to.vertex.Id, from.vertex.Id})
+		}
+	default:
+		delete(f.tunnelFlow, [2]int{to.vertex.Id, from.vertex.Id})
+	}
+}
+
+// paths decomposes the current flow into the paths it carries from the start room to the end room,
+// without the start room, sorted by length.
+func (f *flowNetwork) paths() [][]string {
+	paths := [][]string{}
+	for _, first := range f.start.Adjacent {
+		if !f.tunnelFlow[[2]int{f.start.Id, first.Id}] {
+			continue
+		}
+		path := []string{first.Key}
+		for room := first; room != f.end; {
+			for _, next := range room.Adjacent {
+				if f.tunnelFlow[[2]int{room.Id, next.Id}] {
+					room = next
+					break
+				}
+			}
+			path = append(path, room.Key)
+		}
+		paths = append(paths, path)
+	}
+	return Sort(paths)
+}
@@ -0,0 +1,81 @@
+package functions
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// FormatColony reads a colony from r and returns it in canonical form, written by WriteColonyOptions with opts.
+// The colony is read in relaxed mode, so whitespace is normalised and blank lines are dropped,
+// and a tunnel declared again (in either direction) is dropped instead of being reported as a duplicate,
+// the comment lines in front of it moving to the tunnel that is kept.
+// It returns the first error ParseColony reports on what is left.
+// A JSON colony stays JSON: it is checked and written again by WriteColonyJSON, in declaration order.
+func FormatColony(r io.Reader, opts WriteOptions) ([]byte, error) {
+	var cleaned bytes.Buffer
+	seen := map[[2]string]bool{}
+	pending := []string{}
+	moved := map[[2]string][]string{}
+	rooms := map[string]bool{}
+	reader := bufio.NewReader(r)
+	if isJSONInput(reader) {
+		Colony, err := ParseColonyOptions(reader, ParseOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var formatted bytes.Buffer
+		if err := WriteColonyJSON(&formatted, Colony); err != nil {
+			return nil, err
+		}
+		return formatted.Bytes(), nil
+	}
+	for first := true; ; first = false {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, newParseError(ErrReadInput, "failed to read input: %v", err)
+		}
+		line = normalizeLine(line)
+		if !first && line != "" && line[0] != '#' && line[0] != 'L' {
+			if name, _, ok := strings.Cut(line, " "); ok {
+				rooms[name] = true
+			} else if splits := splitTunnel(line, func(name string) bool { return rooms[name] }); len(splits) == 1 {
+				key := TunnelKey(splits[0][0], splits[0][1])
+				if seen[key] {
+					moved[key] = append(moved[key], pending...)
+					pending = pending[:0]
+					line = ""
+				}
+				seen[key] = true
+			}
+		}
+		if line != "" && line[0] == '#' {
+			pending = append(pending, line)
+		} else if line != "" {
+			for _, comment := range pending {
+				cleaned.WriteString(comment + "\n")
+			}
+			pending = pending[:0]
+			cleaned.WriteString(line + "\n")
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	for _, comment := range pending {
+		cleaned.WriteString(comment + "\n")
+	}
+	Colony, err := ParseColonyOptions(&cleaned, ParseOptions{Strictness: RelaxedInput})
+	if err != nil {
+		return nil, err
+	}
+	for _, edge := range Colony.Graph.Edges {
+		edge.Comments = append(edge.Comments, moved[TunnelKey(edge.From, edge.To)]...)
+	}
+	var formatted bytes.Buffer
+	if err := WriteColonyOptions(&formatted, Colony, opts); err != nil {
+		return nil, err
+	}
+	return formatted.Bytes(), nil
+}
@@ -0,0 +1,20 @@
+package functions
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFormatColonyDuplicateTunnel checks that a tunnel declared twice is written once, with the comments
+// of both declarations in front of it.
+func TestFormatColonyDuplicateTunnel(t *testing.T) {
+	input := "3\n##start\na 0 0\nb 1 0\n##end\nc 2 0\n# first link\na-b\n# second link, duplicate\nb-a\nb-c\n# end\n"
+	want := "3\n##start\na 0 0\nb 1 0\n##end\nc 2 0\n# first link\n# second link, duplicate\na-b\nb-c\n# end\n"
+	formatted, err := FormatColony(strings.NewReader(input), WriteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(formatted) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", formatted, want)
+	}
+}
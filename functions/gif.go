@@ -0,0 +1,175 @@
+package functions
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"strconv"
+
+	"lem-in/entities"
+)
+
+// WriteGIF draws an animated replay of the schedule using the room coordinates: the first frame shows the colony,
+// then every turn is shown as framesPerTurn frames where the ants that move slide from their room to the next one.
+// Ants are drawn in the colour of the path they were sent on and a bar at the bottom shows the progress of the replay,
+// updated at the end of each turn. To keep long replays small, only the first frame covers the whole image:
+// the frames of a turn cover the rectangle around the tunnels used during that turn and are drawn over the previous frame.
+// A replay is held in memory until it is encoded, so it is limited to about maxGIFFrames frames: longer schedules get
+// fewer frames per turn, down to one, and then one frame for several turns.
+func WriteGIF(w io.Writer, Colony *Colony, schedule *entities.Schedule, framesPerTurn int) error {
+	const width, height, margin, barHeight, antRadius = 640, 480, 24.0, 8, 3
+	turns := len(schedule.Turns)
+	framesPerTurn = max(1, min(framesPerTurn, maxGIFFrames/max(turns, 1)))
+	turnsPerFrame := max(1, (turns+maxGIFFrames-1)/maxGIFFrames)
+	graph := Colony.Graph
+	layout := NewLayout(graph, width, height-barHeight, margin)
+	palette := color.Palette{
+		color.White,
+		color.RGBA{0xbb, 0xbb, 0xbb, 0xff},
+		color.RGBA{0x33, 0x33, 0x33, 0xff},
+		color.RGBA{0x2c, 0xa0, 0x2c, 0xff},
+		color.RGBA{0xd6, 0x27, 0x28, 0xff},
+	}
+	const tunnelColor, roomColor, startColor, endColor = 1, 2, 3, 4
+	for i := range pathColors {
+		palette = append(palette, hexColor(pathColors[i]))
+	}
+	antColors := map[int]uint8{}
+	for i, ants := range schedule.PathAnts {
+		for _, ant := range ants {
+			antColors[ant] = uint8(5 + i%len(pathColors))
+		}
+	}
+	background := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+	for _, tunnel := range graph.Tunnels() {
+		drawLine(background, layout.Points[graph.GetVertex(tunnel[0]).Id], layout.Points[graph.GetVertex(tunnel[1]).Id], tunnelColor)
+	}
+	for _, vertex := range graph.Vertices {
+		index := uint8(roomColor)
+		if vertex.Key == Colony.Start {
+			index = startColor
+		} else if vertex.Key == Colony.End {
+			index = endColor
+		}
+		drawDisc(background, layout.Points[vertex.Id], 4, index)
+	}
+	point := func(room string) [2]float64 {
+		return layout.Points[graph.GetVertex(room).Id]
+	}
+	positions := map[int]string{}
+	animation := &gif.GIF{Image: []*image.Paletted{background}, Delay: []int{100}}
+	addFrame := func(area image.Rectangle, moving map[int][2]float64, progress float64, delay int) {
+		frame := image.NewPaletted(area, palette)
+		for y := area.Min.Y; y < area.Max.Y; y++ {
+			copy(frame.Pix[frame.PixOffset(area.Min.X, y):frame.PixOffset(area.Max.X, y)], background.Pix[background.PixOffset(area.Min.X, y):background.PixOffset(area.Max.X, y)])
+		}
+		for ant, room := range positions {
+			if _, ok := moving[ant]; !ok && room != Colony.Start && room != Colony.End {
+				drawDisc(frame, point(room), antRadius, antColors[ant])
+			}
+		}
+		for ant, point := range moving {
+			drawDisc(frame, point, antRadius, antColors[ant])
+		}
+		for x := 0; x < int(progress*width); x++ {
+			for y := height - barHeight; y < height; y++ {
+				frame.SetColorIndex(x, y, roomColor)
+			}
+		}
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, delay)
+	}
+	for t := 0; t < turns; t += turnsPerFrame {
+		turn := []entities.Move{}
+		for _, moves := range schedule.Turns[t:min(t+turnsPerFrame, turns)] {
+			turn = append(turn, moves...)
+		}
+		area := image.Rectangle{}
+		for _, move := range turn {
+			for _, p := range [][2]float64{point(move.From), point(move.To)} {
+				dot := image.Rect(int(p[0])-antRadius, int(p[1])-antRadius, int(p[0])+antRadius+1, int(p[1])+antRadius+1)
+				area = area.Union(dot)
+			}
+		}
+		area = area.Intersect(background.Rect)
+		for step := 1; step <= framesPerTurn; step++ {
+			ratio := float64(step) / float64(framesPerTurn)
+			moving := map[int][2]float64{}
+			for _, move := range turn {
+				from, to := point(move.From), point(move.To)
+				moving[move.Ant] = [2]float64{from[0] + (to[0]-from[0])*ratio, from[1] + (to[1]-from[1])*ratio}
+			}
+			frameArea, progress := area, 0.0
+			if step == framesPerTurn {
+				for _, move := range turn {
+					positions[move.Ant] = move.To
+				}
+				moving = nil
+				progress = float64(min(t+turnsPerFrame, turns)) / float64(turns)
+				frameArea = frameArea.Union(image.Rect(0, height-barHeight, int(progress*width), height))
+			}
+			addFrame(frameArea, moving, progress, max(2, 60/framesPerTurn))
+		}
+	}
+	animation.Delay[len(animation.Delay)-1] = 200
+	return gif.EncodeAll(w, animation)
+}
+
+// maxGIFFrames bounds the number of frames of a replay drawn by WriteGIF.
+const maxGIFFrames = 500
+
+// hexColor converts a "#rrggbb" colour of the palette into a color.RGBA.
+func hexColor(hex string) color.RGBA {
+	value, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return color.RGBA{uint8(value >> 16), uint8(value >> 8), uint8(value), 0xff}
+}
+
+// drawLine draws a one pixel wide segment between two points with the given palette index (Bresenham's algorithm).
+func drawLine(img *image.Paletted, from, to [2]float64, index uint8) {
+	x0, y0, x1, y1 := int(from[0]), int(from[1]), int(to[0]), int(to[1])
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		img.SetColorIndex(x0, y0, index)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// drawDisc fills a disc of the given radius centred on a point with the given palette index.
+func drawDisc(img *image.Paletted, center [2]float64, radius int, index uint8) {
+	cx, cy := int(center[0]), int(center[1])
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y <= radius*radius {
+				img.SetColorIndex(cx+x, cy+y, index)
+			}
+		}
+	}
+}
+
+// abs returns the absolute value of an integer.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
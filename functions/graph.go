@@ -1,20 +1,37 @@
 package functions
 
 import (
-	"fmt"
+	"slices"
 
 	"lem-in/entities"
 )
 
 type Network entities.Graph
 
+// TunnelRule tells how many ants may go through the same tunnel during one turn.
+type TunnelRule int
+
+const (
+	// StrictTunnels lets each tunnel be used by a single ant per turn, as the rules of the game state.
+	StrictTunnels TunnelRule = iota
+	// LenientTunnels only limits the number of ants per room, so a tunnel going straight from the start room
+	// to the end room can carry every ant in the same turn.
+	LenientTunnels
+)
+
 // Colony struct represents the ant colony and its main attributes.
-// It holds a reference to the graph (network) of rooms and paths, the starting and ending points, and the total number of ants to be deployed.
+// It holds a reference to the graph (network) of rooms and paths, the starting and ending points, the total number of ants to be deployed,
+// the rule applied to tunnels during a turn, the lines of the input it was parsed from, the warnings found while parsing it,
+// and an optional function receiving the events of the simulation as the ants are deployed.
 type Colony struct {
 	Graph        *Network
 	Start        string
 	End          string
 	NumberOfAnts int
+	TunnelRule   TunnelRule
+	Text         []string
+	Warnings     []*ParseError
+	OnEvent      func(entities.Event)
 }
 
 // NewColony creates and returns a new instance of the Colony struct, initializing it with the provided graph,
@@ -29,24 +46,24 @@ func NewColony(Graph *Network, Start, End string, NumberOfAnts int) *Colony {
 }
 
 // AddVertex adds a new vertex with the given key to the Network if it does not already exist,
-// if the vertex already exists, it returns an error indicating a duplication issue.
+// indexing it by key and giving it the next integer id, if the vertex already exists, it returns an error indicating a duplication issue.
 func (g *Network) AddVertex(key string) error {
-	if !Contains(g.Vertices, key) {
-		g.Vertices = append(g.Vertices, &entities.Vertex{Key: key})
-		return nil
+	if g.Index == nil {
+		g.Index = make(map[string]*entities.Vertex)
 	}
-	return fmt.Errorf("ERROR: invalid data format, duplicated rooms")
+	if _, exists := g.Index[key]; exists {
+		return newParseError(ErrDuplicateRoom, "duplicated rooms")
+	}
+	vertex := &entities.Vertex{Id: len(g.Vertices), Key: key}
+	g.Vertices = append(g.Vertices, vertex)
+	g.Index[key] = vertex
+	return nil
 }
 
-// GetVertex retrieves and returns the Vertex associated with the specified key from the Network,
+// GetVertex retrieves and returns the Vertex associated with the specified key from the Network index,
 // if no vertex with that key exists, it returns nil.
 func (g *Network) GetVertex(key string) *entities.Vertex {
-	for i, vertex := range g.Vertices {
-		if vertex.Key == key {
-			return g.Vertices[i]
-		}
-	}
-	return nil
+	return g.Index[key]
 }
 
 // AddEdge creates a bidirectional connection (tunnel) between two vertices (rooms) in the Network,
@@ -56,65 +73,106 @@ func (g *Network) AddEdge(from, to string) error {
 	toVertex := g.GetVertex(to)
 	if fromVertex == nil || toVertex == nil {
 		if fromVertex == nil {
-			return fmt.Errorf("ERROR: invalid data format, room %s don't exist", from)
+			return newParseError(ErrUnknownRoom, "room %s don't exist", from)
 		}
-		return fmt.Errorf("ERROR: invalid data format, room %s don't exist", to)
+		return newParseError(ErrUnknownRoom, "room %s don't exist", to)
 	} else if Contains(fromVertex.Adjacent, to) || Contains(toVertex.Adjacent, from) {
-		return fmt.Errorf("ERROR: invalid data format, duplicated tunnels")
+		return newParseError(ErrDuplicateTunnel, "duplicated tunnels")
 	} else {
 		fromVertex.Adjacent = append(fromVertex.Adjacent, toVertex)
 		toVertex.Adjacent = append(toVertex.Adjacent, fromVertex)
+		g.Edges = append(g.Edges, &entities.Edge{From: from, To: to})
 	}
 	return nil
 }
 
+// Tunnels returns every tunnel of the Network once, as pairs of room names ordered by the declaration of their first room.
+func (g *Network) Tunnels() [][2]string {
+	tunnels := [][2]string{}
+	for _, vertex := range g.Vertices {
+		for _, neighbor := range vertex.Adjacent {
+			if vertex.Id < neighbor.Id {
+				tunnels = append(tunnels, [2]string{vertex.Key, neighbor.Key})
+			}
+		}
+	}
+	return tunnels
+}
+
 // RemoveEdge deletes the bidirectional connection (tunnel) between two vertices (rooms) in the Network.
 func (g *Network) RemoveEdge(from, to *entities.Vertex) {
+	detachTunnel(from, to)
+	g.Edges = slices.DeleteFunc(g.Edges, func(edge *entities.Edge) bool {
+		return TunnelKey(edge.From, edge.To) == TunnelKey(from.Key, to.Key)
+	})
+}
+
+// detachTunnel removes the tunnel between two vertices from their adjacency lists only, leaving the declared Edges
+// (their order and comments) untouched, so that a search can run without it before attachTunnel puts it back.
+func detachTunnel(from, to *entities.Vertex) {
 	from.Adjacent = RemoveFromSlice(from.Adjacent, to)
 	to.Adjacent = RemoveFromSlice(to.Adjacent, from)
 }
 
+// attachTunnel adds back to the adjacency lists of two vertices the tunnel removed by detachTunnel.
+func attachTunnel(from, to *entities.Vertex) {
+	from.Adjacent = append(from.Adjacent, to)
+	to.Adjacent = append(to.Adjacent, from)
+}
+
 // GetShortPath finds the shortest path from the start vertex to the end vertex in the network,
 // avoiding the source vertex, and returns the path as a slice of strings.
+// The breadth-first search works on vertex ids and remembers the parent of each visited vertex instead of copying paths.
 func (g *Network) GetShortPath(start, end, source string) ([]string, error) {
-	queue := [][]string{{start}}
-	visited := make(map[string]bool)
-	visited[start] = true
+	startVertex := g.GetVertex(start)
+	if startVertex == nil {
+		return []string{}, newParseError(ErrNoPath, "There's no path between start and end")
+	}
+	parent := make([]int, len(g.Vertices))
+	visited := make([]bool, len(g.Vertices))
+	visited[startVertex.Id] = true
+	parent[startVertex.Id] = -1
+	queue := []*entities.Vertex{startVertex}
 	for len(queue) > 0 {
-		path := queue[0]
+		node := queue[0]
 		queue = queue[1:]
-		node := path[len(path)-1]
-		if node == end {
+		if node.Key == end {
+			path := []string{}
+			for id := node.Id; id != -1; id = parent[id] {
+				path = append(path, g.Vertices[id].Key)
+			}
+			slices.Reverse(path)
 			return path, nil
 		}
-		for _, neighbor := range g.GetVertex(node).Adjacent {
+		for _, neighbor := range node.Adjacent {
 			if neighbor.Key == source {
 				continue
 			}
-			if !visited[neighbor.Key] {
-				visited[neighbor.Key] = true
-				newPath := append([]string{}, path...)
-				newPath = append(newPath, neighbor.Key)
-				queue = append(queue, newPath)
+			if !visited[neighbor.Id] {
+				visited[neighbor.Id] = true
+				parent[neighbor.Id] = node.Id
+				queue = append(queue, neighbor)
 			}
 		}
 	}
-	return []string{}, fmt.Errorf("ERROR: invalid data format, There's no path between start and end")
+	return []string{}, newParseError(ErrNoPath, "There's no path between start and end")
 }
 
 // CheckShortestPaths verifies and modifies the provided shortest paths by removing edges
 // between certain rooms and generating new shortest paths, ensuring paths do not return to the source.
+// The tunnels are only detached from the adjacency lists during the search, the declared Edges are left as they are.
 func (g *Network) CheckShortestPaths(shortestPaths [][]string, source, end string) [][]string {
 	newShortestPaths := [][]string{}
 	for i, shortPshortestPath := range shortestPaths {
 		if i > 0 {
 			for j, room := range shortPshortestPath {
 				if j > 0 && ContainsInslice(shortestPaths[0], room) && room != end {
-					if len(g.GetVertex(shortPshortestPath[j-1]).Adjacent) > 2 {
-						g.RemoveEdge(g.GetVertex(shortPshortestPath[j-1]), g.GetVertex(room))
+					previous := g.GetVertex(shortPshortestPath[j-1])
+					if len(previous.Adjacent) > 2 {
+						detachTunnel(previous, g.GetVertex(room))
 						path, _ := g.GetShortPath(shortPshortestPath[0], end, source)
 						newShortestPaths = append(newShortestPaths, path)
-						g.AddEdge(shortPshortestPath[j-1], room)
+						attachTunnel(previous, g.GetVertex(room))
 						break
 					}
 				}
@@ -0,0 +1,69 @@
+package functions
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// largeColony returns a colony of n rooms in lem-in format: a chain from r0 (start) to r<n-1> (end)
+// with a shortcut every other room, so that a search has to go through most of the network.
+func largeColony(n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\n", 10)
+	for i := 0; i < n; i++ {
+		if i == 0 {
+			b.WriteString("##start\n")
+		} else if i == n-1 {
+			b.WriteString("##end\n")
+		}
+		fmt.Fprintf(&b, "r%d %d %d\n", i, i%1000, i/1000)
+	}
+	for i := 0; i+1 < n; i++ {
+		fmt.Fprintf(&b, "r%d-r%d\n", i, i+1)
+		if i%2 == 0 && i+3 < n {
+			fmt.Fprintf(&b, "r%d-r%d\n", i, i+3)
+		}
+	}
+	return b.String()
+}
+
+// benchmarkInputs returns the benchmarked colonies by name: the pluto example and a generated 100k-room colony.
+func benchmarkInputs(b *testing.B) map[string]string {
+	pluto, err := os.ReadFile("../examples/pluto.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return map[string]string{"Pluto": string(pluto), "100kRooms": largeColony(100000)}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for name, input := range benchmarkInputs(b) {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(input)))
+			for i := 0; i < b.N; i++ {
+				if _, err := ParseColonyOptions(strings.NewReader(input), ParseOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetShortPath(b *testing.B) {
+	for name, input := range benchmarkInputs(b) {
+		b.Run(name, func(b *testing.B) {
+			Colony, err := ParseColonyOptions(strings.NewReader(input), ParseOptions{})
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := Colony.Graph.GetShortPath(Colony.Start, Colony.End, Colony.Start); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
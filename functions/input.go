@@ -0,0 +1,72 @@
+package functions
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+// gzipMagic are the first two bytes of every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// input is the reader returned by OpenInput, closing it closes the decompressor and the file it reads from.
+type input struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close closes the decompressor, if any, then the underlying file, and returns the first error met.
+func (in *input) Close() error {
+	var first error
+	for _, closer := range in.closers {
+		if err := closer.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// OpenInput opens the colony input at the given path, "-" standing for the standard input,
+// the content is decompressed on the fly when it starts with the gzip magic bytes, whatever the file name.
+func OpenInput(path string) (io.ReadCloser, error) {
+	in := &input{}
+	var file io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		file = f
+		in.closers = append(in.closers, f)
+	}
+	reader, closer, err := Decompress(file)
+	if err != nil {
+		in.Close()
+		return nil, err
+	}
+	in.Reader = reader
+	if closer != nil {
+		in.closers = append([]io.Closer{closer}, in.closers...)
+	}
+	return in, nil
+}
+
+// Decompress peeks at the first bytes of r and returns a gzip reader over it when they are the gzip magic bytes,
+// along with the closer of that gzip reader, otherwise it returns a reader of the unchanged content and a nil closer.
+func Decompress(r io.Reader) (io.Reader, io.Closer, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+	if !bytes.Equal(magic, gzipMagic) {
+		return buffered, nil, nil
+	}
+	decompressor, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decompressor, decompressor, nil
+}
@@ -0,0 +1,108 @@
+package functions
+
+import (
+	"encoding/json"
+	"io"
+
+	"lem-in/entities"
+)
+
+// jsonReport is the document written by WriteJSON: the file it was read from when several are solved in one run,
+// the parsed colony, the paths chosen for the ants, every turn's moves and the totals of the schedule.
+type jsonReport struct {
+	File   string       `json:"file,omitempty"`
+	Colony jsonColony   `json:"colony"`
+	Paths  []jsonPath   `json:"paths"`
+	Turns  [][]jsonMove `json:"turns"`
+	Totals jsonTotals   `json:"totals"`
+}
+
+type jsonColony struct {
+	Ants    int         `json:"ants"`
+	Start   string      `json:"start"`
+	End     string      `json:"end"`
+	Rooms   []jsonRoom  `json:"rooms"`
+	Tunnels [][2]string `json:"tunnels"`
+}
+
+type jsonRoom struct {
+	Name     string   `json:"name"`
+	X        int      `json:"x"`
+	Y        int      `json:"y"`
+	Comments []string `json:"comments,omitempty"`
+}
+
+type jsonPath struct {
+	Rooms  []string `json:"rooms"`
+	Ants   int      `json:"ants"`
+	AntIds []int    `json:"ant_ids"`
+}
+
+type jsonMove struct {
+	Turn int    `json:"turn"`
+	Ant  int    `json:"ant"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type jsonTotals struct {
+	Turns int `json:"turns"`
+	Moves int `json:"moves"`
+}
+
+// WriteJSON writes one indented JSON document describing the colony (rooms with their coordinates and comments, tunnels, start, end and ant count),
+// the paths of the schedule with the ants assigned to each of them, the moves of every turn and the totals.
+func WriteJSON(w io.Writer, Colony *Colony, schedule *entities.Schedule) error {
+	return WriteJSONFile(w, "", Colony, schedule)
+}
+
+// WriteJSONFile writes the same document as WriteJSON with a leading "file" key holding path (omitted when empty),
+// so that the documents of several colonies written one after the other can be told apart.
+func WriteJSONFile(w io.Writer, path string, Colony *Colony, schedule *entities.Schedule) error {
+	report := jsonReport{
+		File: path,
+		Colony: jsonColony{
+			Ants:    Colony.NumberOfAnts,
+			Start:   Colony.Start,
+			End:     Colony.End,
+			Rooms:   []jsonRoom{},
+			Tunnels: Colony.Graph.Tunnels(),
+		},
+		Paths:  []jsonPath{},
+		Turns:  [][]jsonMove{},
+		Totals: jsonTotals{Turns: schedule.TurnCount, Moves: schedule.TotalMoves},
+	}
+	for _, vertex := range Colony.Graph.Vertices {
+		report.Colony.Rooms = append(report.Colony.Rooms, jsonRoom{Name: vertex.Key, X: vertex.X, Y: vertex.Y, Comments: vertex.Comments})
+	}
+	for i, path := range schedule.Paths {
+		antIds := append([]int{}, schedule.PathAnts[i]...)
+		report.Paths = append(report.Paths, jsonPath{Rooms: path, Ants: len(antIds), AntIds: antIds})
+	}
+	for _, turn := range schedule.Turns {
+		moves := []jsonMove{}
+		for _, move := range turn {
+			moves = append(moves, jsonMove{Turn: move.Turn, Ant: move.Ant, From: move.From, To: move.To})
+		}
+		report.Turns = append(report.Turns, moves)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteJSONError writes a JSON document holding only the given error, so that JSON consumers get a parsable answer on failure.
+func WriteJSONError(w io.Writer, err error) error {
+	return WriteJSONFileError(w, "", err)
+}
+
+// WriteJSONFileError writes the same document as WriteJSONError with a "file" key holding path (omitted when empty).
+func WriteJSONFileError(w io.Writer, path string, err error) error {
+	document := map[string]string{"error": err.Error()}
+	if path != "" {
+		document["file"] = path
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
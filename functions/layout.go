@@ -0,0 +1,48 @@
+package functions
+
+// Layout maps the coordinates of the colony's rooms onto a drawing area of the given size, keeping the aspect ratio
+// and leaving a margin around the rooms, Points holds the position of each room indexed by vertex id.
+type Layout struct {
+	Width  float64
+	Height float64
+	Points [][2]float64
+}
+
+// NewLayout scales the room coordinates of the network so that every room fits in a width x height area with the given margin,
+// rooms that all share the same coordinate on an axis are centred on that axis.
+func NewLayout(g *Network, width, height, margin float64) *Layout {
+	layout := &Layout{Width: width, Height: height, Points: make([][2]float64, len(g.Vertices))}
+	if len(g.Vertices) == 0 {
+		return layout
+	}
+	minX, maxX := g.Vertices[0].X, g.Vertices[0].X
+	minY, maxY := g.Vertices[0].Y, g.Vertices[0].Y
+	for _, vertex := range g.Vertices {
+		minX, maxX = min(minX, vertex.X), max(maxX, vertex.X)
+		minY, maxY = min(minY, vertex.Y), max(maxY, vertex.Y)
+	}
+	scale := 0.0
+	if maxX > minX {
+		scale = (width - 2*margin) / float64(maxX-minX)
+	}
+	if maxY > minY && (scale == 0 || (height-2*margin)/float64(maxY-minY) < scale) {
+		scale = (height - 2*margin) / float64(maxY-minY)
+	}
+	offsetX := (width - scale*float64(maxX-minX)) / 2
+	offsetY := (height - scale*float64(maxY-minY)) / 2
+	for _, vertex := range g.Vertices {
+		layout.Points[vertex.Id] = [2]float64{
+			offsetX + scale*float64(vertex.X-minX),
+			offsetY + scale*float64(vertex.Y-minY),
+		}
+	}
+	return layout
+}
+
+// pathColors is the palette used to tell the paths of a schedule apart in the renderers.
+var pathColors = []string{"#e6194b", "#3cb44b", "#4363d8", "#f58231", "#911eb4", "#42d4f4", "#f032e6", "#9a6324", "#800000", "#808000", "#000075", "#469990"}
+
+// PathColor returns the colour of the path at the given index, cycling through the palette.
+func PathColor(index int) string {
+	return pathColors[index%len(pathColors)]
+}
@@ -0,0 +1,49 @@
+package functions
+
+import (
+	"io"
+	"sort"
+)
+
+// CheckColony parses the whole colony read from r without stopping at the first error and returns every
+// problem found, sorted by line, followed by the problems that aren't tied to a line (missing start or end, no path).
+// Besides the errors ParseColony would report, it reports an end room that can't be reached from the start room,
+// and warns about isolated rooms, dead-end rooms and rooms sharing coordinates.
+func CheckColony(r io.Reader) []*ParseError {
+	return CheckColonyOptions(r, ParseOptions{})
+}
+
+// CheckColonyOptions reports the problems of the colony read from r like CheckColony, reading it with the strictness of opts.
+func CheckColonyOptions(r io.Reader, opts ParseOptions) []*ParseError {
+	p := newColonyParser(opts, true)
+	p.read(r)
+	for _, vertex := range p.graph.Vertices {
+		if len(vertex.Adjacent) == 0 {
+			p.errs = append(p.errs, newWarning(WarnIsolatedRoom, "room %s isn't connected to any tunnel", vertex.Key).at(p.roomLines[vertex.Key], 1, vertex.Key))
+		} else if len(vertex.Adjacent) == 1 && vertex.Key != p.Start && vertex.Key != p.End {
+			p.errs = append(p.errs, newWarning(WarnDeadEndRoom, "room %s is a dead end", vertex.Key).at(p.roomLines[vertex.Key], 1, vertex.Key))
+		}
+	}
+	if p.Start != "" && p.End != "" {
+		if _, err := p.graph.GetShortPath(p.Start, p.End, p.Start); err != nil {
+			p.errs = append(p.errs, err.(*ParseError))
+		}
+	}
+	sort.SliceStable(p.errs, func(i, j int) bool {
+		if p.errs[i].Line == 0 || p.errs[j].Line == 0 {
+			return p.errs[j].Line == 0 && p.errs[i].Line != 0
+		}
+		return p.errs[i].Line < p.errs[j].Line
+	})
+	return p.errs
+}
+
+// HasErrors reports whether the given problems contain at least one error that isn't a warning.
+func HasErrors(problems []*ParseError) bool {
+	for _, problem := range problems {
+		if !problem.Warning {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,330 @@
+package functions
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseOptions tunes how a colony is read, KeepText retains every input line in Colony.Text
+// so that it can be echoed back, leave it off to parse very large colonies with bounded memory.
+// Strictness selects how forgiving the parser is with the layout of the lines.
+type ParseOptions struct {
+	KeepText   bool
+	Strictness Strictness
+}
+
+// Strictness is the level of tolerance of the parser: StrictInput, the default, accepts exactly what the reference checker accepts,
+// RelaxedInput trims every line and collapses the whitespace inside it, skips blank lines and warns about unknown ## commands.
+type Strictness int
+
+const (
+	StrictInput Strictness = iota
+	RelaxedInput
+)
+
+// colonyParser holds the state built while streaming a colony line by line: the network of rooms,
+// the start and end rooms, the number of ants, the previous line (for ##start and ##end), the comment lines
+// waiting to be attached to the next room or tunnel, the number of lines read and of lines parsed (blank lines being skipped
+// in relaxed mode), the warnings met outside collect mode, whether the input was JSON (and its document when the text is kept), and in collect mode every problem found so far.
+type colonyParser struct {
+	graph        *Network
+	text         []string
+	keepText     bool
+	strictness   Strictness
+	previous     string
+	comments     []string
+	lines        int
+	parsed       int
+	warnings     []*ParseError
+	json         bool
+	document     strings.Builder
+	Start        string
+	End          string
+	NumberOfAnts int
+	startFound   bool
+	endFound     bool
+	collect      bool
+	errs         []*ParseError
+	roomLines    map[string]int
+	coordinates  map[string]string
+}
+
+// newColonyParser creates an empty colonyParser, when collect is true parsing goes on after an error
+// and the line of each room is remembered to report warnings about it.
+func newColonyParser(opts ParseOptions, collect bool) *colonyParser {
+	p := &colonyParser{
+		graph:      &Network{},
+		keepText:   opts.KeepText,
+		strictness: opts.Strictness,
+		collect:    collect,
+	}
+	if collect {
+		p.roomLines = make(map[string]int)
+		p.coordinates = make(map[string]string)
+	}
+	return p
+}
+
+// ParseFile opens the colony file at the given path and parses it with ParseColony,
+// it returns an error if the file can't be opened or if its content is not a valid colony.
+func ParseFile(path string) (*Colony, error) {
+	return ParseFileOptions(path, ParseOptions{KeepText: true})
+}
+
+// ParseFileOptions opens the colony file at the given path with OpenInput, so "-" reads the standard input
+// and gzip-compressed files are accepted, and parses it with ParseColonyOptions.
+func ParseFileOptions(path string, opts ParseOptions) (*Colony, error) {
+	file, err := OpenInput(path)
+	if err != nil {
+		return nil, newParseError(ErrReadInput, "failed to open file: %v ", err)
+	}
+	defer file.Close()
+	return ParseColonyOptions(file, opts)
+}
+
+// ParseColony reads a colony description from r and constructs a graph representation of rooms and tunnels.
+// It returns a Colony holding the network of rooms, the start room, the end room, the number of ants
+// and the lines read from the input, or the first error encountered during parsing.
+func ParseColony(r io.Reader) (*Colony, error) {
+	return ParseColonyOptions(r, ParseOptions{KeepText: true})
+}
+
+// ParseColonyOptions streams the colony description from r line by line, building the network incrementally,
+// lines of any length are accepted and they are only kept in Colony.Text when opts.KeepText is set.
+// In relaxed mode the lines are kept as normalised and the warnings found end up in Colony.Warnings.
+// A colony written as a JSON object is recognised by its opening brace and read with the same checks,
+// its Colony.Text then holds the colony in lem-in format as written by WriteColony, or the JSON document itself
+// when the colony has no lem-in form (a tunnel between hyphenated room names that reads ambiguously both ways).
+func ParseColonyOptions(r io.Reader, opts ParseOptions) (*Colony, error) {
+	p := newColonyParser(opts, false)
+	if err := p.read(r); err != nil {
+		return nil, err
+	}
+	Colony := NewColony(p.graph, p.Start, p.End, p.NumberOfAnts)
+	Colony.Text = p.text
+	Colony.Warnings = p.warnings
+	if p.json && opts.KeepText {
+		var text strings.Builder
+		if err := WriteColony(&text, Colony); err != nil {
+			text.Reset()
+			text.WriteString(strings.TrimSpace(p.document.String()))
+		}
+		Colony.Text = strings.Split(strings.TrimSuffix(text.String(), "\n"), "\n")
+	}
+	return Colony, nil
+}
+
+// read parses r as a JSON colony when it starts with an opening brace and as a lem-in text colony otherwise.
+func (p *colonyParser) read(r io.Reader) *ParseError {
+	reader := bufio.NewReader(r)
+	if isJSONInput(reader) {
+		p.json = true
+		if p.keepText {
+			return p.parseJSON(io.TeeReader(reader, &p.document))
+		}
+		return p.parseJSON(reader)
+	}
+	return p.parse(reader)
+}
+
+// parse streams the lines of r into parseLine, in collect mode the errors are recorded
+// and parsing goes on, otherwise the first error is returned.
+func (p *colonyParser) parse(r io.Reader) *ParseError {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return p.report(newParseError(ErrReadInput, "failed to read input: %v", err))
+		}
+		if len(line) == 0 && err == io.EOF {
+			break
+		}
+		line = strings.TrimSuffix(line, "\n")
+		line = strings.TrimSuffix(line, "\r")
+		number := p.lines
+		p.lines++
+		if p.strictness == RelaxedInput {
+			line = normalizeLine(line)
+		}
+		if line != "" || p.strictness == StrictInput {
+			if p.keepText {
+				p.text = append(p.text, line)
+			}
+			if perr := p.report(p.parseLine(number, line)); perr != nil {
+				return perr
+			}
+			p.previous = line
+			p.parsed++
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	if p.parsed == 0 {
+		return p.report(newParseError(ErrEmptyFile, "file is empty"))
+	}
+	p.graph.Comments = append(p.graph.Comments, p.comments...)
+	p.comments = nil
+	if p.Start == "" {
+		if err := p.report(newParseError(ErrMissingStart, "missing start room")); err != nil {
+			return err
+		}
+	}
+	if p.End == "" {
+		if err := p.report(newParseError(ErrMissingEnd, "missing end room")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// report records err in collect mode and returns nil so that parsing goes on,
+// otherwise it returns err unchanged.
+func (p *colonyParser) report(err *ParseError) *ParseError {
+	if err != nil && p.collect {
+		p.errs = append(p.errs, err)
+		return nil
+	}
+	return err
+}
+
+// parseLine parses the line at index i (the ant count, a room, a tunnel or a comment) into the network
+// and returns the error found on it, if any, the first line parsed holds the ant count.
+func (p *colonyParser) parseLine(i int, line string) *ParseError {
+	if p.parsed == 0 {
+		NumberOfAnts, err := strconv.Atoi(line)
+		if err != nil {
+			return newParseError(ErrInvalidAntCount, "invalid ant count: %s", line).at(1, 1, line)
+		}
+		if NumberOfAnts < 1 {
+			return newParseError(ErrInvalidAntCount, "invalid number of Ants").at(1, 1, line)
+		}
+		p.NumberOfAnts = NumberOfAnts
+		return nil
+	}
+	if len(line) == 0 {
+		return newParseError(ErrInvalidLine, "invalid line format").at(i+1, 1, line)
+	} else if line[0] != '#' && line[0] != 'L' {
+		if strings.Contains(line, " ") {
+			room := strings.Split(line, " ")
+			if len(room) != 3 {
+				return newParseError(ErrInvalidRoom, "invalid room format: %s", line).at(i+1, 1, line)
+			}
+			x, err := strconv.Atoi(room[1])
+			if err != nil {
+				return newParseError(ErrInvalidCoordinates, "invalid room coordinates: %s", line).at(i+1, len(room[0])+2, room[1])
+			}
+			y, err := strconv.Atoi(room[2])
+			if err != nil {
+				return newParseError(ErrInvalidCoordinates, "invalid room coordinates: %s", line).at(i+1, len(room[0])+len(room[1])+3, room[2])
+			}
+			err = p.graph.AddVertex(room[0])
+			if err != nil {
+				return err.(*ParseError).at(i+1, 1, room[0])
+			}
+			vertex := p.graph.GetVertex(room[0])
+			vertex.X, vertex.Y = x, y
+			vertex.Comments, p.comments = p.comments, nil
+			if p.collect {
+				p.roomLines[room[0]] = i + 1
+				position := room[1] + " " + room[2]
+				if other, ok := p.coordinates[position]; ok {
+					p.errs = append(p.errs, newWarning(WarnSharedCoordinates, "room %s shares coordinates with room %s", room[0], other).at(i+1, len(room[0])+2, position))
+				} else {
+					p.coordinates[position] = room[0]
+				}
+			}
+			if p.previous == "##start" {
+				if p.startFound {
+					return newParseError(ErrMultipleStart, "multiple ##start detected at line: %s", line).at(i+1, 1, line)
+				}
+				p.Start = room[0]
+				p.startFound = true
+			} else if p.previous == "##end" {
+				if p.endFound {
+					return newParseError(ErrMultipleEnd, "multiple ##end detected at line: %s", line).at(i+1, 1, line)
+				}
+				p.End = room[0]
+				p.endFound = true
+			}
+		} else if strings.Contains(line, "-") {
+			splits := splitTunnel(line, func(name string) bool { return p.graph.GetVertex(name) != nil })
+			var edge [2]string
+			switch {
+			case len(splits) == 1:
+				edge = splits[0]
+			case len(splits) > 1:
+				options := []string{}
+				for _, split := range splits {
+					options = append(options, split[0]+" to "+split[1])
+				}
+				return newParseError(ErrAmbiguousTunnel, "ambiguous tunnel %s, it could link %s", line, strings.Join(options, " or ")).at(i+1, 1, line)
+			case strings.Count(line, "-") == 1:
+				edge[0], edge[1], _ = strings.Cut(line, "-")
+			default:
+				return newParseError(ErrInvalidTunnel, "invalid tunnel format, no two declared rooms match: %s", line).at(i+1, 1, line)
+			}
+			if edge[0] == edge[1] {
+				return newParseError(ErrCircularTunnel, "Circular tunnel not allowed: %s", line).at(i+1, 1, line)
+			}
+			err := p.graph.AddEdge(edge[0], edge[1])
+			if err != nil {
+				if p.graph.GetVertex(edge[0]) != nil && p.graph.GetVertex(edge[1]) == nil {
+					return err.(*ParseError).at(i+1, len(edge[0])+2, edge[1])
+				}
+				return err.(*ParseError).at(i+1, 1, edge[0])
+			}
+			tunnel := p.graph.Edges[len(p.graph.Edges)-1]
+			tunnel.Comments, p.comments = p.comments, nil
+		} else {
+			return newParseError(ErrInvalidLine, "invalid line format: %s", line).at(i+1, 1, line)
+		}
+	} else if line[0] != '#' {
+		return newParseError(ErrInvalidRoomName, "room shouldn't start with L or #: %s", line).at(i+1, 1, line)
+	} else {
+		if p.strictness == RelaxedInput && strings.HasPrefix(line, "##") && line != "##start" && line != "##end" {
+			p.warn(newWarning(WarnUnknownCommand, "unknown command %s, treated as a comment", line).at(i+1, 1, line))
+		}
+		p.comments = append(p.comments, line)
+	}
+	return nil
+}
+
+// warn records a warning among the problems in collect mode, otherwise among the warnings returned with the colony.
+func (p *colonyParser) warn(warning *ParseError) {
+	if p.collect {
+		p.errs = append(p.errs, warning)
+	} else {
+		p.warnings = append(p.warnings, warning)
+	}
+}
+
+// normalizeLine is how relaxed mode reads a line: surrounding whitespace (including a stray \r) is trimmed,
+// a room line has its fields separated by single spaces, the spaces around the hyphen of a tunnel are removed,
+// and comments keep their inner spacing. Blank lines become empty and are skipped by the caller.
+func normalizeLine(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "#") {
+		return line
+	}
+	fields := strings.Fields(line)
+	if (len(fields) != 3 || fields[1] == "-") && strings.Contains(line, "-") {
+		return strings.Join(fields, "")
+	}
+	return strings.Join(fields, " ")
+}
+
+// splitTunnel returns every way of reading the tunnel line as two room names joined by a hyphen for which declared
+// reports both rooms as declared, so that room names containing hyphens can be linked: "north-gate-hall" is
+// north-gate to hall when those rooms exist, and ambiguous when rooms north and gate-hall exist as well.
+func splitTunnel(line string, declared func(name string) bool) [][2]string {
+	splits := [][2]string{}
+	for i := 0; i < len(line); i++ {
+		if line[i] == '-' && declared(line[:i]) && declared(line[i+1:]) {
+			splits = append(splits, [2]string{line[:i], line[i+1:]})
+		}
+	}
+	return splits
+}
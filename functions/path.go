@@ -8,6 +8,8 @@ import (
 
 // CleanDuplicatedCombinations filters out duplicate path combinations from the pathCombinations map,
 // keeping only unique combinations based on their string representation after sorting them by starting vertex adjacency.
+// The unique combinations are numbered in the order of their string representation, so that the keys, and the combination
+// picked among equally good ones, are the same on every run.
 func CleanDuplicatedCombinations(pathCombinations map[int][][]string, Colony *Colony) map[int][][]string {
 	for key, pathCombination := range pathCombinations {
 		pathCombinations[key] = SortByStartAdjacent(Colony, pathCombination)
@@ -18,11 +20,14 @@ func CleanDuplicatedCombinations(pathCombinations map[int][][]string, Colony *Co
 		combinationStr := fmt.Sprintf("%v", combination)
 		uniqueCombinations[combinationStr] = combination
 	}
+	combinationStrs := make([]string, 0, len(uniqueCombinations))
+	for combinationStr := range uniqueCombinations {
+		combinationStrs = append(combinationStrs, combinationStr)
+	}
+	sort.Strings(combinationStrs)
 	newPathCombinations := make(map[int][][]string)
-	i := 0
-	for _, combination := range uniqueCombinations {
-		newPathCombinations[i] = combination
-		i++
+	for i, combinationStr := range combinationStrs {
+		newPathCombinations[i] = uniqueCombinations[combinationStr]
 	}
 	return newPathCombinations
 }
@@ -54,6 +59,35 @@ func SortByStartAdjacent(Colony *Colony, paths [][]string) [][]string {
 	return paths
 }
 
+// calculatePathLimits determines how many ants go on each of the given paths for the colony, with LenientTunnels a path made of
+// the tunnel between the start and the end rooms carries every ant in a single turn, so it receives all of them.
+func (c *Colony) calculatePathLimits(paths [][]string) []int {
+	if c.TunnelRule == LenientTunnels && len(paths[0]) == 1 && paths[0][0] == c.End {
+		limits := make([]int, len(paths))
+		limits[0] = c.NumberOfAnts
+		return limits
+	}
+	return calculatePathLimits(paths, c.NumberOfAnts)
+}
+
+// CombinationTurns returns the number of turns needed to move the ants along the paths of a combination with the given limits,
+// it is the latest arrival among the used paths, where the last of limit ants sent on a path of length n arrives at turn limit+n-1,
+// except for the direct start-end tunnel under LenientTunnels which brings all its ants in the first turn.
+func (c *Colony) CombinationTurns(paths [][]string, limits []int) int {
+	turns := 0
+	for i, path := range paths {
+		if limits[i] == 0 {
+			continue
+		}
+		pathTurns := limits[i] + len(path) - 1
+		if c.TunnelRule == LenientTunnels && len(path) == 1 && path[0] == c.End {
+			pathTurns = 1
+		}
+		turns = max(turns, pathTurns)
+	}
+	return turns
+}
+
 // calculatePathLimits determines how many ants can be allocated to each path based on their lengths and the total ant count,
 // ensuring that shorter paths receive more ants first until all ants are allocated or no more paths can be filled.
 func calculatePathLimits(paths [][]string, antCount int) []int {
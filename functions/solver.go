@@ -0,0 +1,79 @@
+package functions
+
+import (
+	"sort"
+
+	"lem-in/entities"
+)
+
+// Solver is a strategy that routes the colony's ants from the start room to the end room,
+// it returns the schedule of their moves or an error when the ants can't reach the end.
+type Solver interface {
+	Solve(Colony *Colony) (*entities.Schedule, error)
+}
+
+// ShortestPathsSolver is the original strategy: it takes the shortest path from every neighbour of the start room,
+// adjusts them with CheckShortestPaths, builds and cleans the path combinations and deploys the ants on the best one.
+type ShortestPathsSolver struct{}
+
+// MaxFlowSolver deploys the ants on the vertex-disjoint paths found by max-flow, as GetDisjointPaths does.
+type MaxFlowSolver struct{}
+
+var solvers = map[string]Solver{}
+
+func init() {
+	RegisterSolver("default", ShortestPathsSolver{})
+	RegisterSolver("maxflow", MaxFlowSolver{})
+}
+
+// RegisterSolver makes a solver available under the given name, replacing any solver already registered with that name.
+func RegisterSolver(name string, solver Solver) {
+	solvers[name] = solver
+}
+
+// GetSolver returns the solver registered under the given name and whether it exists.
+func GetSolver(name string) (Solver, bool) {
+	solver, ok := solvers[name]
+	return solver, ok
+}
+
+// SolverNames returns the names of all registered solvers in alphabetical order.
+func SolverNames() []string {
+	names := []string{}
+	for name := range solvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Solve retrieves the shortest paths from every neighbour of the start room, sorts them, generates the path combinations,
+// cleans the duplicated ones and deploys the ants, it returns an error if no neighbour of the start room leads to the end.
+func (ShortestPathsSolver) Solve(Colony *Colony) (*entities.Schedule, error) {
+	graph := Colony.Graph
+	shortestPaths := [][]string{}
+	for _, vertex := range graph.GetVertex(Colony.Start).Adjacent {
+		path, err := graph.GetShortPath(vertex.Key, Colony.End, Colony.Start)
+		if err != nil {
+			continue
+		}
+		shortestPaths = append(shortestPaths, path)
+	}
+	if len(shortestPaths) == 0 {
+		return nil, newParseError(ErrNoPath, "There's no path between start and end")
+	}
+	shortestPaths = Sort(shortestPaths)
+	shortestPaths = graph.CheckShortestPaths(shortestPaths, Colony.Start, Colony.End)
+	pathCombinations := graph.GetPathCombinations(shortestPaths, Colony)
+	pathCombinations = CleanDuplicatedCombinations(pathCombinations, Colony)
+	return DeployAntArmy(pathCombinations, Colony), nil
+}
+
+// Solve deploys the ants on the best of the vertex-disjoint path sets found after each max-flow augmentation.
+func (MaxFlowSolver) Solve(Colony *Colony) (*entities.Schedule, error) {
+	pathSets, err := Colony.Graph.GetFlowPathSets(Colony)
+	if err != nil {
+		return nil, err
+	}
+	return DeployAntArmy(pathSets, Colony), nil
+}
@@ -0,0 +1,62 @@
+package functions
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"lem-in/entities"
+)
+
+// WriteSVG draws the colony as an SVG image using the room coordinates: tunnels as grey lines, rooms as labelled circles
+// with the start room in green and the end room in red, and each path of the schedule highlighted in its own colour,
+// with a legend giving the number of ants sent on it.
+func WriteSVG(w io.Writer, Colony *Colony, schedule *entities.Schedule) error {
+	const width, height, margin, legendLine = 1000.0, 800.0, 40.0, 18.0
+	graph := Colony.Graph
+	layout := NewLayout(graph, width, height, margin)
+	legendHeight := legendLine * float64(len(schedule.Paths)+1)
+	radius := 8.0
+	if len(graph.Vertices) > 200 {
+		radius = 4
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%g\" height=\"%g\" viewBox=\"0 0 %g %g\" font-family=\"sans-serif\" font-size=\"11\">\n", width, height+legendHeight, width, height+legendHeight)
+	fmt.Fprintf(&b, "<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+	b.WriteString("<g stroke=\"#bbbbbb\" stroke-width=\"1\">\n")
+	for _, tunnel := range graph.Tunnels() {
+		from, to := layout.Points[graph.GetVertex(tunnel[0]).Id], layout.Points[graph.GetVertex(tunnel[1]).Id]
+		fmt.Fprintf(&b, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\"/>\n", from[0], from[1], to[0], to[1])
+	}
+	b.WriteString("</g>\n")
+	for i, path := range schedule.Paths {
+		points := []string{}
+		for _, room := range path {
+			point := layout.Points[graph.GetVertex(room).Id]
+			points = append(points, fmt.Sprintf("%.1f,%.1f", point[0], point[1]))
+		}
+		fmt.Fprintf(&b, "<polyline points=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"4\" stroke-opacity=\"0.8\"/>\n", strings.Join(points, " "), PathColor(i))
+	}
+	for _, vertex := range graph.Vertices {
+		point := layout.Points[vertex.Id]
+		fill := "#ffffff"
+		if vertex.Key == Colony.Start {
+			fill = "#2ca02c"
+		} else if vertex.Key == Colony.End {
+			fill = "#d62728"
+		}
+		fmt.Fprintf(&b, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%g\" fill=\"%s\" stroke=\"#333333\"/>\n", point[0], point[1], radius, fill)
+		fmt.Fprintf(&b, "<text x=\"%.1f\" y=\"%.1f\">%s</text>\n", point[0]+radius+2, point[1]-radius, html.EscapeString(vertex.Key))
+	}
+	y := height + legendLine
+	fmt.Fprintf(&b, "<text x=\"%g\" y=\"%.1f\">%d ants from %s to %s in %d turns</text>\n", margin, y, Colony.NumberOfAnts, html.EscapeString(Colony.Start), html.EscapeString(Colony.End), schedule.TurnCount)
+	for i, path := range schedule.Paths {
+		y += legendLine
+		fmt.Fprintf(&b, "<rect x=\"%g\" y=\"%.1f\" width=\"24\" height=\"6\" fill=\"%s\"/>\n", margin, y-6, PathColor(i))
+		fmt.Fprintf(&b, "<text x=\"%g\" y=\"%.1f\">path %d: %d ants, %d moves</text>\n", margin+32, y, i+1, len(schedule.PathAnts[i]), len(path)-1)
+	}
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
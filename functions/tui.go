@@ -0,0 +1,186 @@
+package functions
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"lem-in/entities"
+)
+
+// ASCIIMap draws a colony on a grid of characters: tunnels as dots, rooms as 'o', the start and end rooms as 'S' and 'E',
+// and rooms holding an ant as '@'. Cells holds the position of each room on the grid indexed by vertex id.
+type ASCIIMap struct {
+	Colony *Colony
+	Width  int
+	Height int
+	Cells  [][2]int
+}
+
+// NewASCIIMap places the rooms of the colony on a width x height grid using their coordinates,
+// when auto is set or when two rooms would share a cell, the rooms are laid out by AutoLayout instead.
+func NewASCIIMap(Colony *Colony, width, height int, auto bool) *ASCIIMap {
+	graph := Colony.Graph
+	var layout *Layout
+	if !auto {
+		layout = NewLayout(graph, float64(width-1), float64(height-1), 0)
+		if layoutCollides(layout) {
+			layout = nil
+		}
+	}
+	if layout == nil {
+		layout = AutoLayout(graph, Colony.Start, float64(width-1), float64(height-1))
+	}
+	asciiMap := &ASCIIMap{Colony: Colony, Width: width, Height: height, Cells: make([][2]int, len(graph.Vertices))}
+	for id, point := range layout.Points {
+		asciiMap.Cells[id] = [2]int{int(math.Round(point[0])), int(math.Round(point[1]))}
+	}
+	return asciiMap
+}
+
+// layoutCollides reports whether two rooms of the layout fall on the same cell once rounded.
+func layoutCollides(layout *Layout) bool {
+	cells := map[[2]int]bool{}
+	for _, point := range layout.Points {
+		cell := [2]int{int(math.Round(point[0])), int(math.Round(point[1]))}
+		if cells[cell] {
+			return true
+		}
+		cells[cell] = true
+	}
+	return false
+}
+
+// AutoLayout places the rooms in columns by their distance (in tunnels) from the start room, ignoring their coordinates,
+// rooms that can't be reached from the start room go in a last column. The columns and the rooms of each column
+// are spread evenly over a width x height area.
+func AutoLayout(g *Network, start string, width, height float64) *Layout {
+	layout := &Layout{Width: width, Height: height, Points: make([][2]float64, len(g.Vertices))}
+	distance := make([]int, len(g.Vertices))
+	for i := range distance {
+		distance[i] = -1
+	}
+	columns := [][]int{}
+	if startVertex := g.GetVertex(start); startVertex != nil {
+		distance[startVertex.Id] = 0
+		queue := []*entities.Vertex{startVertex}
+		for len(queue) > 0 {
+			vertex := queue[0]
+			queue = queue[1:]
+			for len(columns) <= distance[vertex.Id] {
+				columns = append(columns, []int{})
+			}
+			columns[distance[vertex.Id]] = append(columns[distance[vertex.Id]], vertex.Id)
+			for _, neighbor := range vertex.Adjacent {
+				if distance[neighbor.Id] == -1 {
+					distance[neighbor.Id] = distance[vertex.Id] + 1
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+	}
+	unreachable := []int{}
+	for id, d := range distance {
+		if d == -1 {
+			unreachable = append(unreachable, id)
+		}
+	}
+	if len(unreachable) > 0 {
+		columns = append(columns, unreachable)
+	}
+	for c, column := range columns {
+		x := 0.0
+		if len(columns) > 1 {
+			x = width * float64(c) / float64(len(columns)-1)
+		}
+		for r, id := range column {
+			y := height / 2
+			if len(column) > 1 {
+				y = height * float64(r) / float64(len(column)-1)
+			}
+			layout.Points[id] = [2]float64{x, y}
+		}
+	}
+	return layout
+}
+
+// Render draws the colony after the given number of turns of the schedule (0 for the initial state).
+// Rooms entered during that turn are highlighted with ANSI inverse video when color is set, and the drawing is followed
+// by the moves of the turn, the occupied rooms with their ant, and the number of ants waiting and arrived.
+func (m *ASCIIMap) Render(schedule *entities.Schedule, turn int, color bool) string {
+	graph := m.Colony.Graph
+	grid := make([][]string, m.Height)
+	for y := range grid {
+		grid[y] = make([]string, m.Width)
+		for x := range grid[y] {
+			grid[y][x] = " "
+		}
+	}
+	for _, tunnel := range graph.Tunnels() {
+		from, to := m.Cells[graph.GetVertex(tunnel[0]).Id], m.Cells[graph.GetVertex(tunnel[1]).Id]
+		steps := max(abs(to[0]-from[0]), abs(to[1]-from[1]))
+		for s := 1; s < steps; s++ {
+			x := from[0] + int(math.Round(float64((to[0]-from[0])*s)/float64(steps)))
+			y := from[1] + int(math.Round(float64((to[1]-from[1])*s)/float64(steps)))
+			grid[y][x] = "."
+		}
+	}
+	positions := map[string]int{}
+	waiting, arrived := m.Colony.NumberOfAnts, 0
+	for _, moves := range schedule.Turns[:turn] {
+		for _, move := range moves {
+			if move.From == m.Colony.Start {
+				waiting--
+			} else {
+				delete(positions, move.From)
+			}
+			if move.To == m.Colony.End {
+				arrived++
+			} else {
+				positions[move.To] = move.Ant
+			}
+		}
+	}
+	entered := map[string]bool{}
+	tokens := []string{}
+	if turn > 0 {
+		for _, move := range schedule.Turns[turn-1] {
+			entered[move.To] = true
+			tokens = append(tokens, FormatMove(move))
+		}
+	}
+	for _, vertex := range graph.Vertices {
+		symbol := "o"
+		if vertex.Key == m.Colony.Start {
+			symbol = "S"
+		} else if vertex.Key == m.Colony.End {
+			symbol = "E"
+		} else if _, occupied := positions[vertex.Key]; occupied {
+			symbol = "@"
+		}
+		if color && entered[vertex.Key] {
+			symbol = "\x1b[7m" + symbol + "\x1b[0m"
+		}
+		cell := m.Cells[vertex.Id]
+		grid[cell[1]][cell[0]] = symbol
+	}
+	var b strings.Builder
+	for _, row := range grid {
+		b.WriteString(strings.TrimRight(strings.Join(row, ""), " "))
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "\nTurn %d/%d: %s\n", turn, len(schedule.Turns), strings.Join(tokens, " "))
+	rooms := []string{}
+	for room := range positions {
+		rooms = append(rooms, room)
+	}
+	sort.Strings(rooms)
+	occupied := []string{}
+	for _, room := range rooms {
+		occupied = append(occupied, fmt.Sprintf("%s:L%d", room, positions[room]))
+	}
+	fmt.Fprintf(&b, "Occupied: %s\n", strings.Join(occupied, " "))
+	fmt.Fprintf(&b, "Waiting in %s: %d, arrived in %s: %d\n", m.Colony.Start, waiting, m.Colony.End, arrived)
+	return b.String()
+}
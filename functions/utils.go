@@ -1,12 +1,8 @@
 package functions
 
 import (
-	"bufio"
 	"fmt"
-	"os"
 	"sort"
-	"strconv"
-	"strings"
 
 	"lem-in/entities"
 )
@@ -41,6 +37,20 @@ func ContainsInslice(slice []string, key string) bool {
 	return false
 }
 
+// TunnelKey returns the key identifying the tunnel between two rooms whatever the direction it is walked in.
+func TunnelKey(from, to string) [2]string {
+	if from > to {
+		from, to = to, from
+	}
+	return [2]string{from, to}
+}
+
+// canUseTunnel reports whether an ant may walk the tunnel between from and to given the tunnels already used this turn,
+// with LenientTunnels any tunnel may be used again.
+func (c *Colony) canUseTunnel(usedTunnels map[[2]string]bool, from, to string) bool {
+	return c.TunnelRule == LenientTunnels || !usedTunnels[TunnelKey(from, to)]
+}
+
 // DeleteInSlice removes the slice at the specified index from the 2D slice shortestPaths and returns the new slice.
 func DeleteInSlice(shortestPaths [][]string, index int) [][]string {
 	newSlice := [][]string{}
@@ -69,110 +79,20 @@ func GetNumberOfSteps(pathLimits []int,pathCombinations [][]string) int {
 	return minSteps
 }
 
-// PrintMovements prints the movements in a formatted way, separating each step with a space and adding a newline after each movement.
-func PrintMovements(movements [][]string) {
-	for _, movement := range movements {
-		for i, step := range movement {
-			fmt.Print(step)
-			if i < len(movement)-1 {
-				fmt.Print(" ")
-			}
-		}
-		fmt.Println()
-	}
+// FormatMove returns the classic "Lx-y" notation of a move, where x is the ant number and y the destination room.
+func FormatMove(move entities.Move) string {
+	return fmt.Sprintf("L%d-%s", move.Ant, move.To)
 }
 
-// Parser reads input from a file and constructs a graph representation of rooms and tunnels.
-// It returns a network of rooms, the lines read from the file, the start room,
-// the end room, the number of ants, and any error encountered during parsing.
-func Parser() (*Network, []string, string, string, int, error) {
-	startFound := false
-	endFound := false
-	args := os.Args[1:]
-	if len(args) != 1 {
-		return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, expected one argument (file name)")
-	}
-	file, err := os.Open(args[0])
-	if err != nil {
-		return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, failed to open file: %v ", err)
-	}
-	defer file.Close()
-	scanner := bufio.NewScanner(file)
-	scanner.Split(bufio.ScanLines)
-	var text []string
-	for scanner.Scan() {
-		text = append(text, scanner.Text())
-	}
-	if len(text) == 0 {
-		return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, file is empty")
-	}
-	NumberOfAnts, err := strconv.Atoi(text[0])
-	if err != nil {
-		return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, invalid ant count: %s", text[0])
-	}
-	if NumberOfAnts < 1 {
-		return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, invalid number of Ants")
-	}
-	graph := &Network{}
-	var Start, End string
-	for i, line := range text {
-		if len(line) == 0 {
-			return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, invalid line format")
-		} else if line[0] != '#' && line[0] != 'L' {
-			if strings.Contains(line, " ") {
-				room := strings.Split(line, " ")
-				if len(room) != 3 {
-					return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, invalid room format: %s", line)
-				}
-				_, err := strconv.Atoi(room[1])
-				if err != nil {
-					return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, invalid room coordinates: %s", line)
-				}
-				_, err = strconv.Atoi(room[2])
-				if err != nil {
-					return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, invalid room coordinates: %s", line)
-				}
-				err = graph.AddVertex(room[0])
-				if err != nil {
-					return nil, nil, "", "", 0, err
-				}
-				if i > 0 && text[i-1] == "##start" {
-					if startFound {
-						return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, multiple ##start detected at line: %s", line)
-					}
-					Start = room[0]
-					startFound = true
-				} else if i > 0 && text[i-1] == "##end" {
-					if endFound {
-						return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, multiple ##end detected at line: %s", line)
-					}
-					End = room[0]
-					endFound = true
-				}
-			} else if strings.Contains(line, "-") {
-				edge := strings.Split(line, "-")
-				if len(edge) != 2 {
-					return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, invalid tunnel format: %s", line)
-				}
-				if edge[0] == edge[1] {
-					return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, Circular tunnel not allowed: %s", line)
-				}
-				err := graph.AddEdge(edge[0], edge[1])
-				if err != nil {
-					return nil, nil, "", "", 0, err
-				}
-			} else if i > 0 && !strings.Contains(line, "-") && !strings.Contains(line, " ") {
-				return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, invalid line format: %s", line)
+// PrintMovements prints the moves of the schedule in the classic format, separating each move of a turn with a space and adding a newline after each turn.
+func PrintMovements(schedule *entities.Schedule) {
+	for _, turn := range schedule.Turns {
+		for i, move := range turn {
+			fmt.Print(FormatMove(move))
+			if i < len(turn)-1 {
+				fmt.Print(" ")
 			}
-		} else if line[0] != '#' {
-			return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, room shouldn't start with L or #: %s", line)
 		}
+		fmt.Println()
 	}
-	if Start == "" {
-		return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, missing start room")
-	}
-	if End == "" {
-		return nil, nil, "", "", 0, fmt.Errorf("ERROR: invalid data format, missing end room")
-	}
-	return graph, text, Start, End, NumberOfAnts, nil
 }
@@ -0,0 +1,141 @@
+package functions
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"lem-in/entities"
+)
+
+// WriteOptions tunes the order in which WriteColonyOptions lays out a colony, Sorted writes the rooms sorted by name
+// and the tunnels sorted by their two rooms instead of in declaration order.
+// A sorted colony has the same rooms and tunnels but its rooms list their neighbours in another order,
+// which can change how ties between equally good paths are broken.
+type WriteOptions struct {
+	Sorted bool
+}
+
+// WriteColony serializes the colony in canonical lem-in format: the ant count, then every room in declaration order
+// preceded by its comment lines and by ##start or ##end, then every tunnel in declaration order as a-b with a before b
+// (or as b-a when a-b would be ambiguous between hyphenated room names), preceded by its comment lines, and last the comment lines that aren't attached to a room or a tunnel.
+// Parsing the output gives back the same rooms, coordinates, tunnels, comments, start, end and ant count.
+// It returns an error, without writing anything, when the colony couldn't be parsed back.
+func WriteColony(w io.Writer, Colony *Colony) error {
+	return WriteColonyOptions(w, Colony, WriteOptions{})
+}
+
+// WriteColonyOptions serializes the colony like WriteColony, in the order selected by opts.
+func WriteColonyOptions(w io.Writer, Colony *Colony, opts WriteOptions) error {
+	if err := checkWritable(Colony); err != nil {
+		return err
+	}
+	vertices := Colony.Graph.Vertices
+	declared := func(name string) bool { return Colony.Graph.GetVertex(name) != nil }
+	tunnels := []*entities.Edge{}
+	for _, edge := range Colony.Graph.Edges {
+		tunnel := *edge
+		tunnel.From, tunnel.To, _ = orientTunnel(edge, declared)
+		tunnels = append(tunnels, &tunnel)
+	}
+	if opts.Sorted {
+		vertices = slices.Clone(vertices)
+		sort.Slice(vertices, func(i, j int) bool { return vertices[i].Key < vertices[j].Key })
+		sort.Slice(tunnels, func(i, j int) bool {
+			if tunnels[i].From != tunnels[j].From {
+				return tunnels[i].From < tunnels[j].From
+			}
+			return tunnels[i].To < tunnels[j].To
+		})
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\n", Colony.NumberOfAnts)
+	for _, vertex := range vertices {
+		for _, comment := range vertex.Comments {
+			if comment != "##start" && comment != "##end" {
+				b.WriteString(comment + "\n")
+			}
+		}
+		if vertex.Key == Colony.Start {
+			b.WriteString("##start\n")
+		} else if vertex.Key == Colony.End {
+			b.WriteString("##end\n")
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", vertex.Key, vertex.X, vertex.Y)
+	}
+	for _, tunnel := range tunnels {
+		for _, comment := range tunnel.Comments {
+			b.WriteString(comment + "\n")
+		}
+		fmt.Fprintf(&b, "%s-%s\n", tunnel.From, tunnel.To)
+	}
+	for _, comment := range Colony.Graph.Comments {
+		b.WriteString(comment + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// checkWritable returns the error ParseColony would report on the output of WriteColony, if any:
+// a missing ant count, start or end room, a room name that can't be written on a room line, a tunnel between hyphenated
+// room names that would read as another tunnel as well whichever way it is written, or a comment that isn't one.
+func checkWritable(Colony *Colony) error {
+	if Colony.NumberOfAnts < 1 {
+		return newParseError(ErrInvalidAntCount, "invalid number of Ants")
+	}
+	if Colony.Graph.GetVertex(Colony.Start) == nil {
+		return newParseError(ErrMissingStart, "missing start room")
+	}
+	if Colony.Graph.GetVertex(Colony.End) == nil {
+		return newParseError(ErrMissingEnd, "missing end room")
+	}
+	for _, vertex := range Colony.Graph.Vertices {
+		if vertex.Key == "" || vertex.Key[0] == 'L' || vertex.Key[0] == '#' || strings.ContainsAny(vertex.Key, " \r\n") {
+			return newParseError(ErrInvalidRoomName, "room name can't be written: %q", vertex.Key)
+		}
+		if err := checkComments(vertex.Comments); err != nil {
+			return err
+		}
+	}
+	declared := func(name string) bool { return Colony.Graph.GetVertex(name) != nil }
+	for _, edge := range Colony.Graph.Edges {
+		if _, _, ok := orientTunnel(edge, declared); !ok {
+			return newParseError(ErrAmbiguousTunnel, "tunnel %s-%s can't be written, it would be ambiguous", edge.From, edge.To)
+		}
+		if err := checkComments(edge.Comments); err != nil {
+			return err
+		}
+	}
+	return checkComments(Colony.Graph.Comments)
+}
+
+// orientTunnel returns the rooms of the tunnel in the order WriteColony writes them, the smaller name first unless
+// that line would read as another tunnel as well, in which case they are written the other way round.
+// ok is false when the tunnel is ambiguous both ways and can't be written at all.
+func orientTunnel(edge *entities.Edge, declared func(name string) bool) (from, to string, ok bool) {
+	from, to = edge.From, edge.To
+	if to < from {
+		from, to = to, from
+	}
+	if len(splitTunnel(from+"-"+to, declared)) == 1 {
+		return from, to, true
+	}
+	return to, from, len(splitTunnel(to+"-"+from, declared)) == 1
+}
+
+// isComment reports whether the line would be read back as a single comment line.
+func isComment(line string) bool {
+	return strings.HasPrefix(line, "#") && !strings.ContainsAny(line, "\r\n")
+}
+
+// checkComments returns an error for the first line that wouldn't be read back as a comment.
+func checkComments(comments []string) error {
+	for _, comment := range comments {
+		if !isComment(comment) {
+			return newParseError(ErrInvalidLine, "comment can't be written: %q", comment)
+		}
+	}
+	return nil
+}
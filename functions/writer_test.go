@@ -0,0 +1,53 @@
+package functions
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestWriteColonyRoundTrip parses colonies with hyphenated room names and comments, writes them back with WriteColony
+// and checks that parsing the output gives the same rooms, tunnels and comments.
+func TestWriteColonyRoundTrip(t *testing.T) {
+	colonies := map[string]string{
+		"ReversesAmbiguousTunnel": "3\n##start\nb-c 0 0\na 1 0\na-b 2 0\n##end\nc 3 0\n# from b-c\nb-c-a\na-c\n",
+		"SwapsUnambiguousTunnel":  "2\n##start\nnorth-gate 0 0\n# hall\nhall 1 0\n##end\ncellar 2 0\n# gate to hall\nnorth-gate-hall\nhall-cellar\n# trailing\n",
+	}
+	for name, input := range colonies {
+		t.Run(name, func(t *testing.T) {
+			Colony, err := ParseColony(strings.NewReader(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var written strings.Builder
+			if err := WriteColony(&written, Colony); err != nil {
+				t.Fatal(err)
+			}
+			reparsed, err := ParseColony(strings.NewReader(written.String()))
+			if err != nil {
+				t.Fatalf("parsing the written colony: %v\n%s", err, written.String())
+			}
+			if reparsed.Start != Colony.Start || reparsed.End != Colony.End || reparsed.NumberOfAnts != Colony.NumberOfAnts {
+				t.Errorf("start, end or ants changed:\n%s", written.String())
+			}
+			for _, vertex := range Colony.Graph.Vertices {
+				other := reparsed.Graph.GetVertex(vertex.Key)
+				if other == nil || other.X != vertex.X || other.Y != vertex.Y || !slices.Equal(other.Comments, vertex.Comments) {
+					t.Errorf("room %s changed:\n%s", vertex.Key, written.String())
+				}
+			}
+			if len(reparsed.Graph.Edges) != len(Colony.Graph.Edges) {
+				t.Fatalf("got %d tunnels, want %d:\n%s", len(reparsed.Graph.Edges), len(Colony.Graph.Edges), written.String())
+			}
+			for i, edge := range Colony.Graph.Edges {
+				other := reparsed.Graph.Edges[i]
+				if TunnelKey(other.From, other.To) != TunnelKey(edge.From, edge.To) || !slices.Equal(other.Comments, edge.Comments) {
+					t.Errorf("tunnel %s-%s changed:\n%s", edge.From, edge.To, written.String())
+				}
+			}
+			if !slices.Equal(reparsed.Graph.Comments, Colony.Graph.Comments) {
+				t.Errorf("trailing comments changed:\n%s", written.String())
+			}
+		})
+	}
+}
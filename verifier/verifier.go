@@ -0,0 +1,152 @@
+package verifier
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"lem-in/functions"
+)
+
+// Violation describes the first rule broken by a transcript, with the 1-based turn and the ant
+// involved (0 when the violation isn't tied to a single ant).
+type Violation struct {
+	Turn int
+	Ant  int
+	Msg  string
+}
+
+// Error returns the violation formatted with its turn and ant.
+func (v *Violation) Error() string {
+	if v.Ant == 0 {
+		return fmt.Sprintf("ERROR: turn %d: %s", v.Turn, v.Msg)
+	}
+	return fmt.Sprintf("ERROR: turn %d, ant %d: %s", v.Turn, v.Ant, v.Msg)
+}
+
+// Result summarizes a legal transcript: the number of turns it takes and the number of moves it contains.
+type Result struct {
+	Turns int
+	Moves int
+}
+
+// ReadTranscript reads the turns of an "Lx-room" transcript from r, one turn per line.
+// When r holds the full program output (the echoed colony, a blank line, then the moves) the echoed part is skipped,
+// it is recognised by its first line being an ant count, so that a transcript holding only moves is read whole.
+func ReadTranscript(r io.Reader) ([][]string, error) {
+	lines := []string{}
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("ERROR: failed to read transcript: %v", err)
+		}
+		if len(line) > 0 {
+			lines = append(lines, strings.TrimRight(line, "\r\n"))
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	if len(lines) > 0 {
+		if _, err := strconv.Atoi(strings.TrimSpace(lines[0])); err == nil {
+			for i, line := range lines {
+				if line == "" {
+					lines = lines[i+1:]
+					break
+				}
+			}
+		}
+	}
+	turns := [][]string{}
+	for _, line := range lines {
+		if line != "" {
+			turns = append(turns, strings.Fields(line))
+		}
+	}
+	return turns, nil
+}
+
+// Verify replays the turns of a transcript against the colony and checks every rule of the simulation:
+// ants only move along tunnels, an intermediate room holds at most one ant at the end of a turn, each tunnel is used
+// at most once per turn (unless the colony uses LenientTunnels), each ant moves at most once per turn, and every ant ends in the end room.
+// It returns the first violation found, or a Result when the transcript is legal.
+func Verify(Colony *functions.Colony, turns [][]string) (*Result, error) {
+	positions := make([]string, Colony.NumberOfAnts+1)
+	for ant := range positions {
+		positions[ant] = Colony.Start
+	}
+	occupants := map[string]int{}
+	arrived := 0
+	moves := 0
+	for t, turn := range turns {
+		turnNumber := t + 1
+		moved := map[int]bool{}
+		usedTunnels := map[[2]string]int{}
+		for _, token := range turn {
+			ant, room, err := parseMove(token, Colony)
+			if err != nil {
+				return nil, &Violation{Turn: turnNumber, Ant: ant, Msg: err.Error()}
+			}
+			if moved[ant] {
+				return nil, &Violation{Turn: turnNumber, Ant: ant, Msg: "ant moves more than once in the same turn"}
+			}
+			moved[ant] = true
+			from := positions[ant]
+			if from == Colony.End {
+				return nil, &Violation{Turn: turnNumber, Ant: ant, Msg: "ant moves after reaching the end room"}
+			}
+			if !functions.Contains(Colony.Graph.GetVertex(from).Adjacent, room) {
+				return nil, &Violation{Turn: turnNumber, Ant: ant, Msg: fmt.Sprintf("no tunnel between %s and %s", from, room)}
+			}
+			tunnel := functions.TunnelKey(from, room)
+			if other, used := usedTunnels[tunnel]; used && Colony.TunnelRule == functions.StrictTunnels {
+				return nil, &Violation{Turn: turnNumber, Ant: ant, Msg: fmt.Sprintf("tunnel %s-%s already used by ant %d in this turn", from, room, other)}
+			}
+			usedTunnels[tunnel] = ant
+			if from != Colony.Start {
+				occupants[from]--
+			}
+			if room != Colony.End {
+				occupants[room]++
+			} else {
+				arrived++
+			}
+			positions[ant] = room
+			moves++
+		}
+		for _, token := range turn {
+			ant, room, _ := parseMove(token, Colony)
+			if room != Colony.End && room != Colony.Start && occupants[room] > 1 {
+				return nil, &Violation{Turn: turnNumber, Ant: ant, Msg: fmt.Sprintf("room %s holds more than one ant", room)}
+			}
+		}
+	}
+	if arrived != Colony.NumberOfAnts {
+		for ant := 1; ant <= Colony.NumberOfAnts; ant++ {
+			if positions[ant] != Colony.End {
+				return nil, &Violation{Turn: len(turns), Ant: ant, Msg: fmt.Sprintf("ant is in room %s instead of the end room after the last turn", positions[ant])}
+			}
+		}
+	}
+	return &Result{Turns: len(turns), Moves: moves}, nil
+}
+
+// parseMove splits an "Lx-room" token into the ant number and the destination room,
+// checking that the ant belongs to the colony and that the room exists.
+func parseMove(token string, Colony *functions.Colony) (int, string, error) {
+	id, room, found := strings.Cut(strings.TrimPrefix(token, "L"), "-")
+	if !strings.HasPrefix(token, "L") || !found {
+		return 0, "", fmt.Errorf("invalid move %q, expected Lx-room", token)
+	}
+	ant, err := strconv.Atoi(id)
+	if err != nil || ant < 1 || ant > Colony.NumberOfAnts {
+		return 0, "", fmt.Errorf("invalid ant in move %q", token)
+	}
+	if Colony.Graph.GetVertex(room) == nil {
+		return ant, "", fmt.Errorf("room %s don't exist", room)
+	}
+	return ant, room, nil
+}
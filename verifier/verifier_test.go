@@ -0,0 +1,87 @@
+package verifier
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"lem-in/functions"
+)
+
+// example00Moves is the schedule printed for examples/example00.txt.
+const example00Moves = "L1-2\nL1-3 L2-2\nL1-1 L2-3 L3-2\nL2-1 L3-3 L4-2\nL3-1 L4-3\nL4-1\n"
+
+// TestReadTranscript checks that the moves are read the same whether the transcript holds the full program output
+// or only the moves.
+func TestReadTranscript(t *testing.T) {
+	colony, err := os.ReadFile("../examples/example00.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transcripts := map[string]string{
+		"FullOutput":      string(colony) + "\n\n" + example00Moves,
+		"MovesOnly":       example00Moves,
+		"MovesOnlyCRLF":   strings.ReplaceAll(example00Moves, "\n", "\r\n"),
+		"MovesOnlyBlanks": example00Moves + "\n\n",
+	}
+	for name, transcript := range transcripts {
+		t.Run(name, func(t *testing.T) {
+			turns, err := ReadTranscript(strings.NewReader(transcript))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(turns) != 6 || strings.Join(turns[5], " ") != "L4-1" {
+				t.Errorf("got turns %v", turns)
+			}
+		})
+	}
+}
+
+// TestVerify checks that the schedule of example00 is accepted and that transcripts breaking a rule are rejected
+// at the right turn.
+func TestVerify(t *testing.T) {
+	Colony, err := functions.ParseFile("../examples/example00.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		moves string
+		turn  int
+	}{
+		"Legal":            {example00Moves, 0},
+		"NoTunnel":         {"L1-3\n", 1},
+		"UnknownRoom":      {"L1-9\n", 1},
+		"UnknownAnt":       {"L5-2\n", 1},
+		"SharedTunnel":     {"L1-2 L2-2\n", 1},
+		"OccupiedRoom":     {"L1-2\nL2-2\n", 2},
+		"TwoMovesInATurn":  {"L1-2 L1-3\n", 1},
+		"MoveAfterArrival": {example00Moves + "L4-3\n", 7},
+		"AntsLeftBehind":   {"L1-2\nL1-3\nL1-1\n", 3},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			turns, err := ReadTranscript(strings.NewReader(test.moves))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := Verify(Colony, turns)
+			if test.turn == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if result.Turns != 6 || result.Moves != 12 {
+					t.Errorf("got %d turns and %d moves, want 6 and 12", result.Turns, result.Moves)
+				}
+				return
+			}
+			var violation *Violation
+			if !errors.As(err, &violation) {
+				t.Fatalf("got %v, want a violation", err)
+			}
+			if violation.Turn != test.turn {
+				t.Errorf("got %v, want a violation in turn %d", violation, test.turn)
+			}
+		})
+	}
+}
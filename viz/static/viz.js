@@ -0,0 +1,240 @@
+// lem-in visualizer: draws the colony served by /api/colony on a canvas and replays its schedule.
+"use strict";
+
+const colors = ["#e6194b", "#3cb44b", "#4363d8", "#f58231", "#911eb4", "#42d4f4", "#f032e6", "#9a6324", "#800000", "#808000", "#000075", "#469990"];
+const canvas = document.getElementById("map");
+const ctx = canvas.getContext("2d");
+const slider = document.getElementById("turn");
+
+let data = null;        // JSON document of the colony and its schedule
+let rooms = {};         // room name -> {x, y} in map coordinates
+let positions = [];     // positions[k][ant] = room of the ant after k turns
+let antPath = {};       // ant -> index of the path it was sent on
+let time = 0;           // current turn, fractional while ants are moving
+let playing = false;
+let selected = 0;       // ant whose route is shown, 0 for none
+let view = { scale: 1, x: 0, y: 0 };
+
+function load(doc) {
+  if (doc.error) {
+    document.getElementById("summary").innerHTML = '<span class="error"></span>';
+    document.querySelector("#summary .error").textContent = doc.error;
+    return;
+  }
+  data = doc;
+  rooms = {};
+  for (const room of data.colony.rooms) rooms[room.name] = room;
+  antPath = {};
+  data.paths.forEach((path, i) => path.ant_ids.forEach(ant => antPath[ant] = i));
+  positions = [{}];
+  for (let ant = 1; ant <= data.colony.ants; ant++) positions[0][ant] = data.colony.start;
+  for (const turn of data.turns) {
+    const next = Object.assign({}, positions[positions.length - 1]);
+    for (const move of turn) next[move.ant] = move.to;
+    positions.push(next);
+  }
+  slider.max = data.turns.length;
+  time = 0;
+  selected = 0;
+  playing = false;
+  showSummary();
+  fit();
+}
+
+function showSummary() {
+  const c = data.colony;
+  document.getElementById("summary").textContent =
+    `${c.ants} ants from ${c.start} to ${c.end}: ${data.totals.turns} turns, ${data.totals.moves} moves`;
+  const list = document.getElementById("paths");
+  list.innerHTML = "";
+  data.paths.forEach((path, i) => {
+    const div = document.createElement("div");
+    const swatch = document.createElement("span");
+    swatch.className = "swatch";
+    swatch.style.background = colors[i % colors.length];
+    div.appendChild(swatch);
+    div.appendChild(document.createTextNode(`path ${i + 1}: ${path.ants} ants, ${path.rooms.length - 1} moves`));
+    list.appendChild(div);
+  });
+  showAnt();
+}
+
+function showAnt() {
+  const box = document.getElementById("ant");
+  if (!selected) {
+    box.textContent = "Click an ant on the map to see its route.";
+    return;
+  }
+  const lines = [`ant ${selected}, path ${antPath[selected] + 1}`];
+  data.turns.forEach(turn => turn.forEach(move => {
+    if (move.ant === selected) lines.push(`turn ${move.turn}: ${move.from} → ${move.to}`);
+  }));
+  box.innerText = lines.join("\n");
+}
+
+// fit resets the zoom and pan so that every room is visible.
+function fit() {
+  resize();
+  const names = Object.keys(rooms);
+  if (names.length === 0) return;
+  const xs = names.map(n => rooms[n].x), ys = names.map(n => rooms[n].y);
+  const minX = Math.min(...xs), maxX = Math.max(...xs), minY = Math.min(...ys), maxY = Math.max(...ys);
+  const margin = 40;
+  const scale = Math.min((canvas.width - 2 * margin) / Math.max(maxX - minX, 1), (canvas.height - 2 * margin) / Math.max(maxY - minY, 1));
+  view.scale = scale;
+  view.x = (canvas.width - scale * (maxX - minX)) / 2 - scale * minX;
+  view.y = (canvas.height - scale * (maxY - minY)) / 2 - scale * minY;
+}
+
+function resize() {
+  canvas.width = canvas.clientWidth;
+  canvas.height = canvas.clientHeight;
+}
+
+function screen(room) {
+  return [view.x + view.scale * room.x, view.y + view.scale * room.y];
+}
+
+// antPoints returns the screen position of every ant away from the start and end rooms at the current time.
+function antPoints() {
+  const turn = Math.floor(time), ratio = time - turn;
+  const points = {};
+  const before = positions[turn], after = positions[Math.min(turn + 1, positions.length - 1)];
+  for (let ant = 1; ant <= data.colony.ants; ant++) {
+    const from = before[ant], to = ratio > 0 ? after[ant] : from;
+    if (from === to && (from === data.colony.start || from === data.colony.end)) continue;
+    const a = screen(rooms[from]), b = screen(rooms[to]);
+    points[ant] = [a[0] + (b[0] - a[0]) * ratio, a[1] + (b[1] - a[1]) * ratio];
+  }
+  return points;
+}
+
+function draw() {
+  ctx.clearRect(0, 0, canvas.width, canvas.height);
+  if (!data) return;
+  ctx.lineWidth = 1;
+  ctx.strokeStyle = "#bbbbbb";
+  for (const [a, b] of data.colony.tunnels) line(rooms[a], rooms[b]);
+  ctx.lineWidth = 4;
+  ctx.globalAlpha = 0.35;
+  data.paths.forEach((path, i) => {
+    ctx.strokeStyle = colors[i % colors.length];
+    for (let j = 1; j < path.rooms.length; j++) line(rooms[path.rooms[j - 1]], rooms[path.rooms[j]]);
+  });
+  ctx.globalAlpha = 1;
+  if (selected) {
+    ctx.strokeStyle = "#000000";
+    ctx.lineWidth = 3;
+    ctx.setLineDash([6, 4]);
+    data.turns.forEach(turn => turn.forEach(move => { if (move.ant === selected) line(rooms[move.from], rooms[move.to]); }));
+    ctx.setLineDash([]);
+  }
+  const radius = Math.max(3, Math.min(10, view.scale / 4));
+  for (const name in rooms) {
+    const [x, y] = screen(rooms[name]);
+    ctx.beginPath();
+    ctx.arc(x, y, radius, 0, 2 * Math.PI);
+    ctx.fillStyle = name === data.colony.start ? "#2ca02c" : name === data.colony.end ? "#d62728" : "#ffffff";
+    ctx.fill();
+    ctx.strokeStyle = "#333333";
+    ctx.lineWidth = 1;
+    ctx.stroke();
+    ctx.fillStyle = "#333333";
+    ctx.fillText(name, x + radius + 2, y - radius);
+  }
+  const points = antPoints();
+  for (const ant in points) {
+    const [x, y] = points[ant];
+    ctx.beginPath();
+    ctx.arc(x, y, Number(ant) === selected ? radius : radius * 0.7, 0, 2 * Math.PI);
+    ctx.fillStyle = colors[antPath[ant] % colors.length];
+    ctx.fill();
+  }
+  const turn = Math.floor(time);
+  const atEnd = Object.values(positions[turn]).filter(room => room === data.colony.end).length;
+  const atStart = Object.values(positions[turn]).filter(room => room === data.colony.start).length;
+  document.getElementById("label").textContent = `turn ${turn} / ${data.turns.length} — ${atStart} waiting, ${atEnd} arrived`;
+  slider.value = time;
+}
+
+function line(a, b) {
+  const [x1, y1] = screen(a), [x2, y2] = screen(b);
+  ctx.beginPath();
+  ctx.moveTo(x1, y1);
+  ctx.lineTo(x2, y2);
+  ctx.stroke();
+}
+
+let last = null;
+function frame(now) {
+  if (playing && data) {
+    if (last !== null) time = Math.min(data.turns.length, time + (now - last) / 1000 * Number(document.getElementById("speed").value));
+    if (time >= data.turns.length) setPlaying(false);
+  }
+  last = now;
+  draw();
+  requestAnimationFrame(frame);
+}
+
+function setPlaying(value) {
+  playing = value;
+  document.getElementById("play").innerHTML = playing ? "&#10074;&#10074;" : "&#9654;";
+}
+
+document.getElementById("play").onclick = () => {
+  if (data && time >= data.turns.length) time = 0;
+  setPlaying(!playing);
+};
+document.getElementById("forward").onclick = () => { setPlaying(false); time = Math.min(data.turns.length, Math.floor(time) + 1); };
+document.getElementById("back").onclick = () => { setPlaying(false); time = Math.max(0, Math.ceil(time) - 1); };
+document.getElementById("fit").onclick = fit;
+slider.oninput = () => { setPlaying(false); time = Number(slider.value); };
+
+canvas.addEventListener("wheel", event => {
+  event.preventDefault();
+  const factor = event.deltaY < 0 ? 1.15 : 1 / 1.15;
+  view.x = event.offsetX - (event.offsetX - view.x) * factor;
+  view.y = event.offsetY - (event.offsetY - view.y) * factor;
+  view.scale *= factor;
+});
+
+let drag = null;
+canvas.addEventListener("mousedown", event => { drag = { x: event.offsetX, y: event.offsetY, moved: false }; });
+canvas.addEventListener("mousemove", event => {
+  if (!drag) return;
+  const dx = event.offsetX - drag.x, dy = event.offsetY - drag.y;
+  if (Math.abs(dx) + Math.abs(dy) > 2) drag.moved = true;
+  view.x += dx;
+  view.y += dy;
+  drag.x = event.offsetX;
+  drag.y = event.offsetY;
+});
+canvas.addEventListener("mouseup", event => {
+  if (drag && !drag.moved && data) {
+    const points = antPoints();
+    let best = 0, bestDistance = 12 * 12;
+    for (const ant in points) {
+      const distance = (points[ant][0] - event.offsetX) ** 2 + (points[ant][1] - event.offsetY) ** 2;
+      if (distance < bestDistance) { best = Number(ant); bestDistance = distance; }
+    }
+    selected = best;
+    showAnt();
+  }
+  drag = null;
+});
+window.addEventListener("resize", resize);
+
+document.getElementById("file").onchange = async event => {
+  const file = event.target.files[0];
+  if (!file) return;
+  const solver = document.getElementById("solver").value;
+  const response = await fetch("api/solve?solver=" + encodeURIComponent(solver), { method: "POST", body: await file.text() });
+  load(await response.json());
+};
+
+fetch("api/solvers").then(r => r.json()).then(names => {
+  const select = document.getElementById("solver");
+  for (const name of names) select.add(new Option(name, name));
+});
+fetch("api/colony").then(r => r.json()).then(load);
+requestAnimationFrame(frame);
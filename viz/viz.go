@@ -0,0 +1,78 @@
+package viz
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"lem-in/entities"
+	"lem-in/functions"
+)
+
+//go:embed static
+var static embed.FS
+
+// maxColonyBytes bounds the size of a colony posted to "/api/solve".
+const maxColonyBytes = 16 << 20
+
+// Handler returns the HTTP handler of the visualizer: the embedded page and its script under "/",
+// the colony and schedule given at startup as JSON under "/api/colony", the names of the registered solvers
+// under "/api/solvers", and "/api/solve" which parses the colony posted in the request body (up to maxColonyBytes)
+// with opts (shared by every request and never modified), applies the tunnel rule of the startup colony, solves it with the solver named by the "solver" query parameter
+// (the default one when empty) and answers with the same JSON document.
+func Handler(Colony *functions.Colony, schedule *entities.Schedule, opts functions.ParseOptions) http.Handler {
+	mux := http.NewServeMux()
+	files, _ := fs.Sub(static, "static")
+	mux.Handle("/", http.FileServer(http.FS(files)))
+	mux.HandleFunc("/api/colony", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		functions.WriteJSON(w, Colony, schedule)
+	})
+	mux.HandleFunc("/api/solvers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(functions.SolverNames())
+	})
+	mux.HandleFunc("/api/solve", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "expected a POST request with a colony in the body", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		solverName := r.URL.Query().Get("solver")
+		if solverName == "" {
+			solverName = "default"
+		}
+		solver, ok := functions.GetSolver(solverName)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			functions.WriteJSONError(w, fmt.Errorf("ERROR: unknown solver %s, expected one of: %s", solverName, strings.Join(functions.SolverNames(), ", ")))
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxColonyBytes))
+		if err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			functions.WriteJSONError(w, fmt.Errorf("ERROR: failed to read the posted colony: %v", err))
+			return
+		}
+		posted, err := functions.ParseColonyOptions(bytes.NewReader(body), opts)
+		if err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			functions.WriteJSONError(w, err)
+			return
+		}
+		posted.TunnelRule = Colony.TunnelRule
+		solved, err := solver.Solve(posted)
+		if err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			functions.WriteJSONError(w, err)
+			return
+		}
+		functions.WriteJSON(w, posted, solved)
+	})
+	return mux
+}